@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getAPIKeyCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "apikeys")
+}
+
+// disallowedAPIKeyPermissions returns the permissions that must not be granted
+// to a device: everything via "*" and the account and access management
+// permissions, so that a leaked key cannot be used to take over the system.
+func disallowedAPIKeyPermissions(permissions []string) []string {
+	var disallowed []string
+	for _, p := range permissions {
+		if p == models.PermissionAll || strings.HasSuffix(p, ":manage") {
+			disallowed = append(disallowed, p)
+		}
+	}
+	return disallowed
+}
+
+// @Summary Get API Keys
+// @Description List device API keys, newest first. Key values are never returned after creation (requires apikeys:manage)
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.APIKey "List of API keys"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /apikeys [get]
+func GetAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var apiKeys []models.APIKey
+		cursor, err := getAPIKeyCollection().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching API keys"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &apiKeys); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding API keys"})
+			return
+		}
+
+		c.JSON(http.StatusOK, apiKeys)
+	}
+}
+
+// @Summary Create API Key
+// @Description Issue an API key for a device such as a POS terminal or kitchen display. The key is only shown in this response. It grants exactly the listed permissions, which cannot include * or any :manage permission (requires apikeys:manage)
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param apikey body models.APIKeyRequest true "API key details"
+// @Success 201 {object} models.APIKeyCreateResponse "API key created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /apikeys [post]
+func CreateAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.APIKeyRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if unknown := unknownPermissions(req.Permissions); len(unknown) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown permissions", "permissions": unknown})
+			return
+		}
+
+		if disallowed := disallowedAPIKeyPermissions(req.Permissions); len(disallowed) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Permissions cannot be granted to an API key", "permissions": disallowed})
+			return
+		}
+
+		if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+
+		key, prefix, err := helpers.GenerateAPIKey()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating API key"})
+			return
+		}
+
+		apiKey := models.APIKey{
+			ID:          primitive.NewObjectID(),
+			Name:        req.Name,
+			Prefix:      prefix,
+			KeyHash:     helpers.HashToken(key),
+			Permissions: req.Permissions,
+			CreatedBy:   c.GetString("uid"),
+			CreatedAt:   time.Now(),
+			ExpiresAt:   req.ExpiresAt,
+		}
+
+		_, err = getAPIKeyCollection().InsertOne(ctx, apiKey)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "API key created successfully, store it now as it will not be shown again",
+			"key":     key,
+			"api_key": apiKey,
+		})
+	}
+}
+
+// @Summary Revoke API Key
+// @Description Revoke a device API key. Requests using it are rejected immediately (requires apikeys:manage)
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.SuccessResponse "API key revoked"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "API key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /apikeys/{id} [delete]
+func RevokeAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKeyID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(apiKeyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"revoked":    true,
+				"revoked_at": time.Now(),
+			},
+		}
+
+		result, err := getAPIKeyCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"basic-backend/helpers"
 	"basic-backend/models"
 	"context"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,10 +21,52 @@ func getUserCollection() *mongo.Collection {
 	return database.GetCollection(database.Client, "users")
 }
 
+// issueLoginTokens starts a new session for the user on the requesting device
+// and returns its first access/refresh token pair.
+func issueLoginTokens(ctx context.Context, c *gin.Context, user models.User) (token string, refreshToken string, err error) {
+	familyID := primitive.NewObjectID().Hex()
+	token, refreshToken, err = helpers.GenerateAllTokens(user.Email, user.FirstName, user.LastName, user.UserType, user.ID.Hex(), familyID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := createSession(ctx, c, user.ID, familyID, refreshToken); err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
+
+// rehashPassword stores password hashed with the current policy.
+func rehashPassword(ctx context.Context, userID primitive.ObjectID, password string) error {
+	hashedPassword, err := helpers.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = getUserCollection().UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"password": hashedPassword}})
+	return err
+}
+
+func loginResponse(user models.User, token string, refreshToken string) gin.H {
+	return gin.H{
+		"message":       "Login successful",
+		"token":         token,
+		"refresh_token": refreshToken,
+		"user": gin.H{
+			"id":         user.ID,
+			"email":      user.Email,
+			"first_name": user.FirstName,
+			"last_name":  user.LastName,
+			"user_type":  user.UserType,
+		},
+	}
+}
+
 var validate = validator.New()
 
 // @Summary User Signup
-// @Description Register a new user account with email, password, and profile information
+// @Description Register a new customer account with email, password, and profile information. Accounts created here always have the USER role and start unverified; a verification link is emailed. Tokens are only returned when email verification is not required.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -37,18 +81,29 @@ func Signup() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var user models.User
-		if err := c.BindJSON(&user); err != nil {
+		var signupReq models.SignupRequest
+		if err := c.BindJSON(&signupReq); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		validationErr := validate.Struct(user)
+		validationErr := validate.Struct(signupReq)
 		if validationErr != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
 			return
 		}
 
+		// Self-service accounts are always regular users; staff accounts are
+		// created through the admin user-management API.
+		user := models.User{
+			FirstName: signupReq.FirstName,
+			LastName:  signupReq.LastName,
+			Email:     signupReq.Email,
+			Password:  signupReq.Password,
+			Phone:     signupReq.Phone,
+			UserType:  "USER",
+		}
+
 		count, err := getUserCollection().CountDocuments(ctx, bson.M{"email": user.Email})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking email"})
@@ -71,14 +126,9 @@ func Signup() gin.HandlerFunc {
 		user.UpdatedAt = time.Now()
 		user.ID = primitive.NewObjectID()
 
-		token, refreshToken, err := helpers.GenerateAllTokens(user.Email, user.FirstName, user.LastName, user.UserType)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
-			return
-		}
-
-		user.Token = token
-		user.RefreshToken = refreshToken
+		// Tokens are only issued straight away when the email address does not
+		// have to be verified before the first login.
+		requireVerification := emailVerificationRequired()
 
 		_, insertErr := getUserCollection().InsertOne(ctx, user)
 		if insertErr != nil {
@@ -86,23 +136,47 @@ func Signup() gin.HandlerFunc {
 			return
 		}
 
+		if err := sendVerificationEmail(ctx, user); err != nil {
+			log.Println("Failed to send verification email:", err)
+		}
+
+		if requireVerification {
+			user.Password = ""
+			c.JSON(http.StatusCreated, gin.H{
+				"message": "User created successfully, check your email to verify your account",
+				"user":    user,
+			})
+			return
+		}
+
+		token, refreshToken, err := issueLoginTokens(ctx, c, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
+			return
+		}
+
+		user.Password = ""
+		helpers.SetAuthCookie(c, token)
 		c.JSON(http.StatusCreated, gin.H{
-			"message": "User created successfully",
-			"token":   token,
-			"user":    user,
+			"message":       "User created successfully",
+			"token":         token,
+			"refresh_token": refreshToken,
+			"user":          user,
 		})
 	}
 }
 
 // @Summary User Login
-// @Description Authenticate user with email and password, returns JWT access token
+// @Description Authenticate user with email and password, returns JWT access token. When the account has two-factor authentication enabled, or its role requires it, a challenge token is returned instead that must be completed at /auth/login/2fa.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param credentials body models.LoginRequest true "Email and Password"
-// @Success 200 {object} models.LoginResponse "Login successful with authentication token and user details"
+// @Success 200 {object} models.LoginResponse "Login successful with authentication token and user details, or a two-factor challenge"
 // @Failure 400 {object} models.ErrorResponse "Invalid request body"
 // @Failure 401 {object} models.ErrorResponse "Invalid email or password"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled or email not verified"
+// @Failure 429 {object} models.ErrorResponse "Too many failed attempts for this account or address; see Retry-After"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /auth/login [post]
 func Login() gin.HandlerFunc {
@@ -118,49 +192,103 @@ func Login() gin.HandlerFunc {
 			return
 		}
 
-		err := getUserCollection().FindOne(ctx, bson.M{"email": loginReq.Email}).Decode(&foundUser)
+		// Refuse locked out accounts and addresses before spending any time
+		// on bcrypt.
+		accountKey := accountThrottleKey(loginReq.Email)
+		ipKey := ipThrottleKey(c.ClientIP())
+		lockedFor, err := loginLockedFor(ctx, accountKey, ipKey)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking login attempts"})
 			return
 		}
 
-		passwordIsValid := helpers.VerifyPassword(foundUser.Password, loginReq.Password)
+		if lockedFor > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(lockedFor.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed login attempts, try again later"})
+			return
+		}
+
+		err = getUserCollection().FindOne(ctx, bson.M{"email": loginReq.Email}).Decode(&foundUser)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+			return
+		}
+
+		passwordIsValid := err == nil && helpers.VerifyPassword(foundUser.Password, loginReq.Password)
 		if !passwordIsValid {
+			if err := recordLoginFailure(ctx, accountKey, accountThrottle); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+				return
+			}
+
+			if err := recordLoginFailure(ctx, ipKey, ipThrottle); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+				return
+			}
+
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 			return
 		}
 
-		token, refreshToken, err := helpers.GenerateAllTokens(foundUser.Email, foundUser.FirstName, foundUser.LastName, foundUser.UserType)
+		if foundUser.Disabled {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+			return
+		}
+
+		if !foundUser.EmailVerified && emailVerificationRequired() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Email address has not been verified"})
+			return
+		}
+
+		// With two-factor authentication the password step only yields a
+		// challenge token that has to be completed at /auth/login/2fa.
+		requireTwoFactor, err := roleRequiresTwoFactor(ctx, foundUser.UserType)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading role"})
 			return
 		}
 
-		update := bson.M{
-			"$set": bson.M{
-				"token":         token,
-				"refresh_token": refreshToken,
-				"updated_at":    time.Now(),
-			},
+		// Upgrade the stored hash while the plaintext password is at hand if
+		// the hashing policy has changed since it was made. Refused logins
+		// have returned by now, so only accounts that may sign in are written.
+		if helpers.PasswordNeedsRehash(foundUser.Password) {
+			if err := rehashPassword(ctx, foundUser.ID, loginReq.Password); err != nil {
+				log.Println("Failed to rehash password:", err)
+			}
 		}
 
-		_, updateErr := getUserCollection().UpdateOne(ctx, bson.M{"_id": foundUser.ID}, update)
-		if updateErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating tokens"})
+		// A correct password alone does not clear the recorded failures of
+		// two-factor accounts; LoginTwoFactor clears them once the code has
+		// been verified too.
+		if foundUser.TOTPEnabled || requireTwoFactor {
+			challengeToken, err := helpers.GenerateChallengeToken(foundUser.ID.Hex())
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"message":             "Two-factor authentication required",
+				"two_factor_required": true,
+				"enrollment_required": !foundUser.TOTPEnabled,
+				"challenge_token":     challengeToken,
+			})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Login successful",
-			"token":   token,
-			"user": gin.H{
-				"id":         foundUser.ID,
-				"email":      foundUser.Email,
-				"first_name": foundUser.FirstName,
-				"last_name":  foundUser.LastName,
-				"user_type":  foundUser.UserType,
-			},
-		})
+		if err := clearLoginFailures(ctx, accountKey); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+			return
+		}
+
+		token, refreshToken, err := issueLoginTokens(ctx, c, foundUser)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
+			return
+		}
+
+		helpers.SetAuthCookie(c, token)
+		c.JSON(http.StatusOK, loginResponse(foundUser, token, refreshToken))
 	}
 }
 
@@ -177,7 +305,7 @@ func Login() gin.HandlerFunc {
 func GetUser() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		email := c.GetString("email")
-		
+
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
@@ -192,3 +320,164 @@ func GetUser() gin.HandlerFunc {
 		c.JSON(http.StatusOK, user)
 	}
 }
+
+// @Summary Refresh Tokens
+// @Description Exchange a refresh token for a new access/refresh token pair. The presented refresh token is invalidated; replaying an already rotated token revokes every token in its family.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.RefreshResponse "New token pair"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 401 {object} models.ErrorResponse "Invalid, expired, revoked or reused refresh token"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/refresh [post]
+func RefreshToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.RefreshRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		claims, errMsg := helpers.ValidateToken(req.RefreshToken)
+		if errMsg != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errMsg})
+			return
+		}
+
+		if claims.TokenType != helpers.RefreshTokenType {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+
+		tokenHash := helpers.HashToken(req.RefreshToken)
+		var session models.Session
+		err := getSessionCollection().FindOne(ctx, bson.M{"refresh_token_hash": tokenHash}).Decode(&session)
+		if err == mongo.ErrNoDocuments {
+			// A token that has already been exchanged is being replayed, so
+			// either the client or an attacker holds a stolen copy. Sign the
+			// whole session out.
+			found, err := revokeSession(ctx, bson.M{"previous_token_hashes": tokenHash})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking session"})
+				return
+			}
+
+			if !found {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+				return
+			}
+
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, please log in again"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking refresh token"})
+			return
+		}
+
+		if session.Revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has been revoked"})
+			return
+		}
+
+		var user models.User
+		err = getUserCollection().FindOne(ctx, bson.M{"_id": session.UserID}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			return
+		}
+
+		if user.Disabled {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+			return
+		}
+
+		token, refreshToken, err := helpers.GenerateAllTokens(user.Email, user.FirstName, user.LastName, user.UserType, user.ID.Hex(), session.ID.Hex())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
+			return
+		}
+
+		// Swap the refresh token only if it is still the session's current
+		// one, so two concurrent requests cannot both exchange it.
+		now := time.Now()
+		update := bson.M{
+			"$set": bson.M{
+				"refresh_token_hash": helpers.HashToken(refreshToken),
+				"user_agent":         c.Request.UserAgent(),
+				"ip":                 c.ClientIP(),
+				"last_used_at":       now,
+				"expires_at":         now.Add(helpers.RefreshTokenLifetime),
+			},
+			"$push": bson.M{"previous_token_hashes": tokenHash},
+		}
+
+		result, err := getSessionCollection().UpdateOne(ctx,
+			bson.M{"_id": session.ID, "refresh_token_hash": tokenHash, "revoked": false},
+			update,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error storing refresh token"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+
+		helpers.SetAuthCookie(c, token)
+		c.JSON(http.StatusOK, gin.H{
+			"message":       "Token refreshed successfully",
+			"token":         token,
+			"refresh_token": refreshToken,
+		})
+	}
+}
+
+// @Summary Logout
+// @Description Revoke the access token used for this request and end its session, and clear the auth cookie if enabled
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse "Logged out successfully"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/logout [post]
+func Logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if tokenID := c.GetString("token_id"); tokenID != "" {
+			if err := helpers.RevokeToken(ctx, tokenID, c.GetTime("token_expires_at")); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking token"})
+				return
+			}
+		}
+
+		if familyID := c.GetString("family_id"); familyID != "" {
+			sessionID, _ := primitive.ObjectIDFromHex(familyID)
+			if _, err := revokeSession(ctx, bson.M{"_id": sessionID}); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking session"})
+				return
+			}
+		}
+
+		helpers.ClearAuthCookie(c)
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"basic-backend/models"
+	"basic-backend/money"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Percentages of an order's subtotal added as service charge when no service
+// charge rule applies, and charged as tax on foods without a tax class.
+var (
+	taxRate           float64
+	serviceChargeRate float64
+)
+
+// restaurantLocation is the time zone promotion time windows are given in.
+var restaurantLocation = time.Local
+
+// SetupBilling reads the default tax rate and the service charge rate from
+// TAX_RATE and SERVICE_CHARGE_RATE, given in percent. Both default to 0.
+// TIMEZONE names the restaurant's time zone, such as Europe/London, and
+// defaults to the server's.
+func SetupBilling() error {
+	var err error
+	if name := os.Getenv("TIMEZONE"); name != "" {
+		if restaurantLocation, err = time.LoadLocation(name); err != nil {
+			return fmt.Errorf("TIMEZONE must name a time zone, got %q: %w", name, err)
+		}
+	}
+	if taxRate, err = percentFromEnv("TAX_RATE"); err != nil {
+		return err
+	}
+	if serviceChargeRate, err = percentFromEnv("SERVICE_CHARGE_RATE"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func percentFromEnv(name string) (float64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("%s must be a percentage between 0 and 100, got %q", name, value)
+	}
+	return percent, nil
+}
+
+// loadTaxClasses returns the tax classes with the given IDs keyed by ID.
+func loadTaxClasses(ctx context.Context, ids []string) (map[string]models.TaxClass, error) {
+	objIDs := []primitive.ObjectID{}
+	for _, id := range ids {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+
+	taxClasses := map[string]models.TaxClass{}
+	if len(objIDs) == 0 {
+		return taxClasses, nil
+	}
+
+	cursor, err := getTaxClassCollection().Find(ctx, bson.M{"_id": bson.M{"$in": objIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []models.TaxClass
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	for _, taxClass := range found {
+		taxClasses[taxClass.ID.Hex()] = taxClass
+	}
+	return taxClasses, nil
+}
+
+// completeTotals loads the tax classes of the items of detail, the promotions
+// that may apply to it and its service charge rule, and completes its totals.
+func completeTotals(ctx context.Context, detail *models.OrderDetail) error {
+	ids := make([]string, 0, len(detail.Items))
+	for _, item := range detail.Items {
+		if item.TaxClassID != "" {
+			ids = append(ids, item.TaxClassID)
+		}
+	}
+
+	taxClasses, err := loadTaxClasses(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	promotions, err := loadPromotions(ctx, detail.CouponCodes)
+	if err != nil {
+		return err
+	}
+
+	if detail.PartySize, err = orderPartySize(ctx, detail.Order); err != nil {
+		return err
+	}
+
+	rules, err := loadServiceChargeRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	detail.Totals.ServiceChargeRate = serviceChargeRate
+	detail.Totals.ServiceChargeRule = ""
+	if rule, ok := serviceChargeRuleFor(rules, detail.PartySize, detail.ServiceType); ok {
+		detail.Totals.ServiceChargeRate = rule.Rate
+		detail.Totals.ServiceChargeRule = rule.Name
+	}
+
+	applyPromotions(detail, promotions)
+	computeTotals(detail, taxClasses)
+	return nil
+}
+
+// computeTotals taxes what is left of each line of detail after discounts at
+// its tax class's rate for the order's service type, rounding the tax of each
+// line to minor units with the configured rounding mode, and fills in the tax
+// breakdown, the service charge at the rate set in its totals and the total.
+// Items whose tax class is not set or no longer exists are taxed at the
+// default rate, exclusive of their price.
+func computeTotals(detail *models.OrderDetail, taxClasses map[string]models.TaxClass) {
+	defaultClass := models.TaxClass{Name: "Default", DineInRate: taxRate, TakeawayRate: taxRate}
+
+	taxes := []models.TaxLine{}
+	taxIndex := map[string]int{}
+	tax, exclusiveTax := money.New(0), money.New(0)
+	for i := range detail.Items {
+		line := &detail.Items[i]
+
+		classID := line.TaxClassID
+		taxClass, ok := taxClasses[classID]
+		if !ok {
+			classID = ""
+			taxClass = defaultClass
+		}
+
+		rate := taxClass.Rate(detail.ServiceType)
+		taxable := line.LineTotal.Sub(line.Discount)
+		if taxClass.Inclusive {
+			line.Tax = taxable.IncludedPercent(rate)
+			taxable = taxable.Sub(line.Tax)
+		} else {
+			line.Tax = taxable.Percent(rate)
+			exclusiveTax = exclusiveTax.Add(line.Tax)
+		}
+		line.TaxRate = rate
+		tax = tax.Add(line.Tax)
+
+		j, ok := taxIndex[classID]
+		if !ok {
+			j = len(taxes)
+			taxIndex[classID] = j
+			taxes = append(taxes, models.TaxLine{
+				TaxClassID: classID,
+				Name:       taxClass.Name,
+				Rate:       rate,
+				Inclusive:  taxClass.Inclusive,
+			})
+		}
+		taxes[j].Taxable = taxes[j].Taxable.Add(taxable)
+		taxes[j].Tax = taxes[j].Tax.Add(line.Tax)
+	}
+
+	totals := &detail.Totals
+	totals.Tax = tax
+	totals.Taxes = taxes
+	discounted := totals.Subtotal.Sub(totals.Discount)
+	totals.ServiceCharge = discounted.Percent(totals.ServiceChargeRate)
+	totals.Total = discounted.Add(totals.ServiceCharge).Add(exclusiveTax)
+}
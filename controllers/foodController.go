@@ -4,6 +4,8 @@ import (
 	"basic-backend/database"
 	"basic-backend/models"
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
@@ -85,7 +87,7 @@ func GetFood() gin.HandlerFunc {
 }
 
 // @Summary Create Food
-// @Description Create a new food item in the restaurant menu (Admin only)
+// @Description Create a new food item in the restaurant menu (requires foods:create)
 // @Tags Food
 // @Accept json
 // @Produce json
@@ -113,9 +115,34 @@ func CreateFood() gin.HandlerFunc {
 			return
 		}
 
+		exists, err := referenceExists(ctx, "menus", food.MenuID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking menu"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Menu not found"})
+			return
+		}
+
+		if food.TaxClassID != "" {
+			exists, err := referenceExists(ctx, "taxclasses", food.TaxClassID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking tax class"})
+				return
+			}
+
+			if !exists {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Tax class not found"})
+				return
+			}
+		}
+
 		food.CreatedAt = time.Now()
 		food.UpdatedAt = time.Now()
 		food.ID = primitive.NewObjectID()
+		food.Available = true
 
 		result, err := getFoodCollection().InsertOne(ctx, food)
 		if err != nil {
@@ -132,7 +159,7 @@ func CreateFood() gin.HandlerFunc {
 }
 
 // @Summary Update Food
-// @Description Update an existing food item's information (Admin only)
+// @Description Update an existing food item's information (requires foods:update)
 // @Tags Food
 // @Accept json
 // @Produce json
@@ -163,15 +190,40 @@ func UpdateFood() gin.HandlerFunc {
 			return
 		}
 
+		exists, err := referenceExists(ctx, "menus", food.MenuID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking menu"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Menu not found"})
+			return
+		}
+
+		if food.TaxClassID != "" {
+			exists, err := referenceExists(ctx, "taxclasses", food.TaxClassID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking tax class"})
+				return
+			}
+
+			if !exists {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Tax class not found"})
+				return
+			}
+		}
+
 		food.UpdatedAt = time.Now()
 
 		update := bson.M{
 			"$set": bson.M{
-				"name":       food.Name,
-				"price":      food.Price,
-				"food_image": food.FoodImage,
-				"menu_id":    food.MenuID,
-				"updated_at": food.UpdatedAt,
+				"name":         food.Name,
+				"price":        food.Price,
+				"food_image":   food.FoodImage,
+				"menu_id":      food.MenuID,
+				"tax_class_id": food.TaxClassID,
+				"updated_at":   food.UpdatedAt,
 			},
 		}
 
@@ -190,8 +242,83 @@ func UpdateFood() gin.HandlerFunc {
 	}
 }
 
+// @Summary Set Food Availability
+// @Description Mark a food item as available or sold out (requires foods:availability). Unavailable foods cannot be added to orders.
+// @Tags Food
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Food MongoDB ObjectID" example("507f1f77bcf86cd799439011")
+// @Param request body models.FoodAvailabilityRequest true "New availability"
+// @Success 200 {object} models.SuccessResponse "Food availability updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or request body"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 404 {object} models.ErrorResponse "Food item not found"
+// @Failure 500 {object} models.ErrorResponse "Database error while updating food"
+// @Router /foods/{id}/availability [put]
+func SetFoodAvailability() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		foodID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(foodID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid food ID"})
+			return
+		}
+
+		var req models.FoodAvailabilityRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"available":  *req.Available,
+				"updated_at": time.Now(),
+			},
+		}
+
+		result, err := getFoodCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update food"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Food availability updated successfully"})
+	}
+}
+
+// BackfillFoodAvailability marks foods created before availability was tracked
+// as available so that they can still be ordered.
+func BackfillFoodAvailability() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := getFoodCollection().UpdateMany(ctx,
+		bson.M{"available": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"available": true}},
+	)
+	if err != nil {
+		log.Fatal("Failed to backfill food availability:", err)
+	}
+}
+
 // @Summary Delete Food
-// @Description Permanently delete a food item from the menu (Admin only)
+// @Description Permanently delete a food item from the menu (requires foods:delete). Order items that reference the food are handled according to ON_DELETE_FOOD_ORDER_ITEMS and promotions that target it according to ON_DELETE_FOOD_PROMOTIONS; by default the delete is refused with 409 while either exist.
 // @Tags Food
 // @Accept json
 // @Produce json
@@ -201,6 +328,7 @@ func UpdateFood() gin.HandlerFunc {
 // @Failure 400 {object} models.ErrorResponse "Invalid MongoDB ObjectID format"
 // @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
 // @Failure 404 {object} models.ErrorResponse "Food item not found"
+// @Failure 409 {object} models.ErrorResponse "Still referenced by other records"
 // @Failure 500 {object} models.ErrorResponse "Database error while deleting food"
 // @Router /foods/{id} [delete]
 func DeleteFood() gin.HandlerFunc {
@@ -215,13 +343,23 @@ func DeleteFood() gin.HandlerFunc {
 			return
 		}
 
-		result, err := getFoodCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		var deleted int64
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			var err error
+			deleted, err = deleteDocuments(sessCtx, "foods", bson.M{"_id": objID})
+			return err
+		})
+		var depErr *dependentsError
+		if errors.As(err, &depErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Food is still referenced", "dependents": depErr.Dependents})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete food"})
 			return
 		}
 
-		if result.DeletedCount == 0 {
+		if deleted == 0 {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Food not found"})
 			return
 		}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// What happens to the documents that reference a deleted document.
+const (
+	onDeleteRestrict = "restrict" // refuse the delete while references exist
+	onDeleteCascade  = "cascade"  // delete the referencing documents too
+	onDeleteNullify  = "nullify"  // set the reference to null
+)
+
+// reference describes a field of one collection that holds the hex ID of a
+// document in another, or a list of them if list is set. Nullifying a list
+// reference removes the deleted IDs from the list.
+type reference struct {
+	name     string // configured with ON_DELETE_<NAME>
+	parent   string
+	child    string
+	field    string
+	list     bool
+	onDelete string
+}
+
+var references = []*reference{
+	{name: "menu_foods", parent: "menus", child: "foods", field: "menu_id", onDelete: onDeleteRestrict},
+	{name: "tax_class_foods", parent: "taxclasses", child: "foods", field: "tax_class_id", onDelete: onDeleteRestrict},
+	{name: "table_orders", parent: "tables", child: "orders", field: "table_id", onDelete: onDeleteRestrict},
+	{name: "food_order_items", parent: "foods", child: "orderitems", field: "food_id", onDelete: onDeleteRestrict},
+	{name: "order_order_items", parent: "orders", child: "orderitems", field: "order_id", onDelete: onDeleteCascade},
+	{name: "order_invoices", parent: "orders", child: "invoices", field: "order_id", onDelete: onDeleteRestrict},
+	{name: "food_promotions", parent: "foods", child: "promotions", field: "food_ids", list: true, onDelete: onDeleteRestrict},
+	{name: "menu_promotions", parent: "menus", child: "promotions", field: "menu_ids", list: true, onDelete: onDeleteRestrict},
+}
+
+// SetupDeleteRules reads the on-delete behaviour of each reference from the
+// environment, keeping the default for references that are not configured.
+func SetupDeleteRules() error {
+	for _, ref := range references {
+		name := "ON_DELETE_" + strings.ToUpper(ref.name)
+		value := strings.ToLower(os.Getenv(name))
+		switch value {
+		case "":
+		case onDeleteRestrict, onDeleteCascade, onDeleteNullify:
+			ref.onDelete = value
+		default:
+			return fmt.Errorf("%s must be restrict, cascade or nullify, got %q", name, value)
+		}
+	}
+	return nil
+}
+
+// dependentsError refuses a delete because other documents still reference
+// the documents being deleted. Handlers answer it with 409.
+type dependentsError struct {
+	// Dependents counts the blocking documents per collection.
+	Dependents map[string]int64
+}
+
+func (e *dependentsError) Error() string {
+	return "documents are still referenced"
+}
+
+// referenceExists reports whether the collection has a document with the
+// given hex ID.
+func referenceExists(ctx context.Context, collection string, id string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+
+	count, err := database.GetCollection(database.Client, collection).CountDocuments(ctx, bson.M{"_id": objID})
+	return count > 0, err
+}
+
+// deleteDocuments deletes the documents of collection matching filter and
+// applies the on-delete behaviour of every reference to them. Nothing is
+// changed by this call if a restricted reference exists, but cascades and
+// nullifies of other references may already have been written when it fails,
+// so it must run inside a transaction.
+func deleteDocuments(ctx context.Context, collection string, filter bson.M) (int64, error) {
+	coll := database.GetCollection(database.Client, collection)
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(docs))
+	hexIDs := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+		hexIDs[i] = doc.ID.Hex()
+	}
+
+	blocked := map[string]int64{}
+	for _, ref := range references {
+		if ref.parent != collection || ref.onDelete != onDeleteRestrict {
+			continue
+		}
+
+		count, err := database.GetCollection(database.Client, ref.child).CountDocuments(ctx, bson.M{ref.field: bson.M{"$in": hexIDs}})
+		if err != nil {
+			return 0, err
+		}
+		if count > 0 {
+			blocked[ref.child] += count
+		}
+	}
+
+	if len(blocked) > 0 {
+		return 0, &dependentsError{Dependents: blocked}
+	}
+
+	for _, ref := range references {
+		if ref.parent != collection {
+			continue
+		}
+
+		childFilter := bson.M{ref.field: bson.M{"$in": hexIDs}}
+		switch ref.onDelete {
+		case onDeleteCascade:
+			if _, err := deleteDocuments(ctx, ref.child, childFilter); err != nil {
+				return 0, err
+			}
+		case onDeleteNullify:
+			update := bson.M{"$set": bson.M{ref.field: nil}}
+			if ref.list {
+				update = bson.M{"$pull": bson.M{ref.field: bson.M{"$in": hexIDs}}}
+			}
+
+			_, err := database.GetCollection(database.Client, ref.child).UpdateMany(ctx, childFilter, update)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	result, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
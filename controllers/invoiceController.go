@@ -4,6 +4,7 @@ import (
 	"basic-backend/database"
 	"basic-backend/models"
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -18,7 +19,7 @@ func getInvoiceCollection() *mongo.Collection {
 }
 
 // @Summary Get All Invoices
-// @Description Retrieve a list of all invoices
+// @Description Retrieve a list of invoices. Callers without the invoices:any permission only see their own invoices.
 // @Tags Invoice
 // @Accept json
 // @Produce json
@@ -32,7 +33,7 @@ func GetInvoices() gin.HandlerFunc {
 		defer cancel()
 
 		var invoices []models.Invoice
-		cursor, err := getInvoiceCollection().Find(ctx, bson.M{})
+		cursor, err := getInvoiceCollection().Find(ctx, ownerFilter(c, "invoices:any"))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching invoices"})
 			return
@@ -71,8 +72,11 @@ func GetInvoice() gin.HandlerFunc {
 			return
 		}
 
+		filter := ownerFilter(c, "invoices:any")
+		filter["_id"] = objID
+
 		var invoice models.Invoice
-		err = getInvoiceCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&invoice)
+		err = getInvoiceCollection().FindOne(ctx, filter).Decode(&invoice)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
 			return
@@ -82,15 +86,177 @@ func GetInvoice() gin.HandlerFunc {
 	}
 }
 
+// errOrderNotBillable refuses to invoice an order that is not in a status
+// from which it can move to billed.
+var errOrderNotBillable = errors.New("order cannot be billed in its current status")
+
+// errOrderAlreadyInvoiced refuses a second invoice for an order. Billed orders
+// cannot be billed again anyway; this also covers invoices whose order was
+// moved back by hand.
+var errOrderAlreadyInvoiced = errors.New("order already has an invoice")
+
+// errOrderHasNoItems refuses to invoice an order without items.
+var errOrderHasNoItems = errors.New("order has no items")
+
+// errPromotionExhausted refuses to invoice an order discounted by a promotion
+// that reached its usage limit while the order was being billed.
+var errPromotionExhausted = errors.New("promotion has reached its usage limit")
+
+// billOrder creates an invoice for the order matching filter from the order's
+// items and totals and moves the order to billed. Both happen in one
+// transaction, and the order is only moved if its status has not changed in
+// the meantime, so an order is never billed twice. Every promotion that
+// discounted the order is counted as used once. The invoice starts as pending
+// and is only paid through PayInvoice.
+func billOrder(ctx context.Context, c *gin.Context, filter bson.M, paymentMethod string) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		order, err := loadOrderDetail(sessCtx, filter)
+		if err != nil {
+			return err
+		}
+
+		if len(order.Items) == 0 {
+			return errOrderHasNoItems
+		}
+
+		if _, ok := models.OrderTransitions[order.Status][models.OrderStatusBilled]; !ok {
+			return errOrderNotBillable
+		}
+
+		invoiced, err := getInvoiceCollection().CountDocuments(sessCtx, bson.M{"order_id": order.ID.Hex()})
+		if err != nil {
+			return err
+		}
+		if invoiced > 0 {
+			return errOrderAlreadyInvoiced
+		}
+
+		lines := make([]models.InvoiceLine, len(order.Items))
+		for i, item := range order.Items {
+			lines[i] = models.InvoiceLine{
+				OrderItemID: item.ID.Hex(),
+				FoodID:      item.FoodID,
+				Name:        item.Name,
+				Quantity:    item.Quantity,
+				UnitPrice:   item.UnitPrice,
+				LineTotal:   item.LineTotal,
+				Discount:    item.Discount,
+				TaxClassID:  item.TaxClassID,
+				TaxRate:     item.TaxRate,
+				Tax:         item.Tax,
+			}
+		}
+
+		// The invoice belongs to whoever owns the order so that customers can
+		// see bills created for them by staff.
+		userID := order.UserID
+		if userID == "" {
+			userID = c.GetString("uid")
+		}
+
+		serviceType := order.ServiceType
+		if serviceType == "" {
+			serviceType = models.ServiceTypeDineIn
+		}
+
+		now := time.Now()
+		invoice = models.Invoice{
+			ID:                primitive.NewObjectID(),
+			OrderID:           order.ID.Hex(),
+			ServiceType:       serviceType,
+			PaymentMethod:     paymentMethod,
+			Lines:             lines,
+			Subtotal:          order.Totals.Subtotal,
+			GuestCount:        order.PartySize,
+			ServiceCharge:     order.Totals.ServiceCharge,
+			ServiceChargeRate: order.Totals.ServiceChargeRate,
+			ServiceChargeRule: order.Totals.ServiceChargeRule,
+			Tax:               order.Totals.Tax,
+			Taxes:             order.Totals.Taxes,
+			Discount:          order.Totals.Discount,
+			Discounts:         order.Totals.Discounts,
+			TotalAmount:       order.Totals.Total,
+			PaymentStatus:     models.PaymentStatusPending,
+			UserID:            userID,
+			CreatedBy:         c.GetString("uid"),
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+
+		if _, err := getInvoiceCollection().InsertOne(sessCtx, invoice); err != nil {
+			return err
+		}
+
+		for _, discount := range order.Totals.Discounts {
+			promotionID, err := primitive.ObjectIDFromHex(discount.PromotionID)
+			if err != nil {
+				return err
+			}
+
+			result, err := getPromotionCollection().UpdateOne(sessCtx,
+				bson.M{"_id": promotionID, "$or": bson.A{
+					bson.M{"usage_limit": 0},
+					bson.M{"$expr": bson.M{"$lt": bson.A{"$used_count", "$usage_limit"}}},
+				}},
+				bson.M{"$inc": bson.M{"used_count": 1}},
+			)
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return errPromotionExhausted
+			}
+		}
+
+		result, err := getOrderCollection().UpdateOne(sessCtx,
+			bson.M{"_id": order.ID, "status": order.Status},
+			bson.M{"$set": bson.M{
+				"status": models.OrderStatusBilled,
+				"status_timestamps." + models.OrderStatusBilled: now,
+				"updated_at": now,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errOrderNotBillable
+		}
+		return nil
+	})
+	return invoice, err
+}
+
+// respondBillingError answers an error returned by billOrder.
+func respondBillingError(c *gin.Context, err error) {
+	switch {
+	case err == mongo.ErrNoDocuments:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	case errors.Is(err, errOrderHasNoItems):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order has no items"})
+	case errors.Is(err, errOrderNotBillable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only delivered orders can be billed"})
+	case errors.Is(err, errOrderAlreadyInvoiced):
+		c.JSON(http.StatusConflict, gin.H{"error": "Order already has an invoice"})
+	case errors.Is(err, errPromotionExhausted):
+		c.JSON(http.StatusConflict, gin.H{"error": "A promotion on the order has reached its usage limit"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invoice"})
+	}
+}
+
 // @Summary Create Invoice
-// @Description Create a new invoice
+// @Description Create a new invoice for an order. Works like POST /orders/{id}/invoice but takes the order ID in the body. The invoice starts as pending; use /invoices/{id}/pay to record the payment.
 // @Tags Invoice
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param invoice body models.InvoiceCreateRequest true "Invoice details"
 // @Success 201 {object} models.InvoiceResponse "Invoice created successfully"
-// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 400 {object} models.ErrorResponse "Bad request or order without items"
+// @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Order is not delivered, already has an invoice or uses an exhausted promotion"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /invoices [post]
 func CreateInvoice() gin.HandlerFunc {
@@ -98,47 +264,242 @@ func CreateInvoice() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var invoice models.Invoice
-		if err := c.BindJSON(&invoice); err != nil {
+		var req models.InvoiceCreateRequest
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		validationErr := validate.Struct(invoice)
+		validationErr := validate.Struct(req)
 		if validationErr != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
 			return
 		}
 
-		invoice.CreatedAt = time.Now()
-		invoice.UpdatedAt = time.Now()
-		invoice.ID = primitive.NewObjectID()
+		orderObjID, err := primitive.ObjectIDFromHex(req.OrderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+			return
+		}
+
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = orderObjID
 
-		result, err := getInvoiceCollection().InsertOne(ctx, invoice)
+		invoice, err := billOrder(ctx, c, filter, req.PaymentMethod)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invoice"})
+			respondBillingError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusCreated, gin.H{
 			"message": "Invoice created successfully",
-			"id":      result.InsertedID,
+			"id":      invoice.ID,
+			"invoice": invoice,
+		})
+	}
+}
+
+// @Summary Invoice Order
+// @Description Bill a delivered order: the invoice's lines, subtotal, discounts, service charge, tax and total are copied from the order, the payment starts as pending and the order moves to billed. An order is invoiced once; refunding its invoice does not reopen it.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body models.OrderInvoiceRequest true "Payment method"
+// @Success 201 {object} models.InvoiceResponse "Invoice created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request or order without items"
+// @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Order is not delivered, already has an invoice or uses an exhausted promotion"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /orders/{id}/invoice [post]
+func CreateOrderInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+			return
+		}
+
+		var req models.OrderInvoiceRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = objID
+
+		invoice, err := billOrder(ctx, c, filter, req.PaymentMethod)
+		if err != nil {
+			respondBillingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Invoice created successfully",
+			"id":      invoice.ID,
+			"invoice": invoice,
+		})
+	}
+}
+
+// payableStatuses are the payment statuses of invoices that can be paid.
+var payableStatuses = []string{models.PaymentStatusPending, models.PaymentStatusFailed}
+
+// isStaffMember reports whether id is the ID of a user with a staff role, that
+// is any role but the customers' USER role.
+func isStaffMember(ctx context.Context, id string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+
+	count, err := getUserCollection().CountDocuments(ctx, bson.M{"_id": objID, "usertype": bson.M{"$ne": "USER"}})
+	return count > 0, err
+}
+
+// @Summary Pay Invoice
+// @Description Record the payment of a pending or failed invoice together with an optional tip. The tip is not part of the invoice total; it is credited to staff_id, by default the staff member who created the invoice, and shows up in GET /reports/tips. staff_id must be a user with a staff role and is required for a tip on invoices created without one, such as those created with an API key.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param payment body models.InvoicePaymentRequest true "Payment method, tip and staff member"
+// @Success 200 {object} models.InvoiceResponse "Invoice paid"
+// @Failure 400 {object} models.ErrorResponse "Bad request or unknown staff member"
+// @Failure 404 {object} models.ErrorResponse "Invoice not found"
+// @Failure 409 {object} models.ErrorResponse "Invoice is already paid or refunded"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /invoices/{id}/pay [post]
+func PayInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoiceID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(invoiceID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+			return
+		}
+
+		var req models.InvoicePaymentRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		filter := ownerFilter(c, "invoices:any")
+		filter["_id"] = objID
+
+		var invoice models.Invoice
+		err = getInvoiceCollection().FindOne(ctx, filter).Decode(&invoice)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+
+		if !containsString(payableStatuses, invoice.PaymentStatus) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Invoice is already " + invoice.PaymentStatus})
+			return
+		}
+
+		staffID := req.StaffID
+		if staffID == "" {
+			staffID = invoice.CreatedBy
+		}
+
+		if req.Tip.IsPositive() || req.StaffID != "" {
+			if staffID == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "staff_id is required for a tip on an invoice without a creating staff member"})
+				return
+			}
+
+			isStaff, err := isStaffMember(ctx, staffID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking staff member"})
+				return
+			}
+
+			if !isStaff {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Staff member not found"})
+				return
+			}
+		}
+
+		if !req.Tip.IsPositive() {
+			staffID = ""
+		}
+
+		now := time.Now()
+		invoice.PaymentMethod = req.PaymentMethod
+		invoice.PaymentStatus = models.PaymentStatusPaid
+		invoice.Tip = req.Tip
+		invoice.TipStaffID = staffID
+		invoice.AmountPaid = invoice.TotalAmount.Add(req.Tip)
+		invoice.PaidAt = &now
+		invoice.UpdatedAt = now
+
+		result, err := getInvoiceCollection().UpdateOne(ctx,
+			bson.M{"_id": objID, "payment_status": bson.M{"$in": payableStatuses}},
+			bson.M{"$set": bson.M{
+				"payment_method": invoice.PaymentMethod,
+				"payment_status": invoice.PaymentStatus,
+				"tip":            invoice.Tip,
+				"tip_staff_id":   invoice.TipStaffID,
+				"amount_paid":    invoice.AmountPaid,
+				"paid_at":        invoice.PaidAt,
+				"updated_at":     invoice.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to pay invoice"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Invoice was updated while paying it"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Invoice paid",
+			"id":      invoice.ID,
 			"invoice": invoice,
 		})
 	}
 }
 
 // @Summary Update Invoice
-// @Description Update an existing invoice
+// @Description Update the payment method and status of an invoice. The status can only move from pending to failed, from failed back to pending and from paid to refunded; use /invoices/{id}/pay to record a payment. The order and total of an invoice cannot be changed.
 // @Tags Invoice
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Invoice ID"
-// @Param invoice body models.InvoiceCreateRequest true "Updated invoice details"
+// @Param invoice body models.InvoiceUpdateRequest true "Updated payment details"
 // @Success 200 {object} models.SuccessResponse "Invoice updated successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request"
 // @Failure 404 {object} models.ErrorResponse "Invoice not found"
+// @Failure 409 {object} models.ErrorResponse "Status change not allowed"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /invoices/{id} [put]
 func UpdateInvoice() gin.HandlerFunc {
@@ -153,32 +514,59 @@ func UpdateInvoice() gin.HandlerFunc {
 			return
 		}
 
-		var invoice models.Invoice
-		if err := c.BindJSON(&invoice); err != nil {
+		var req models.InvoiceUpdateRequest
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		invoice.UpdatedAt = time.Now()
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		filter := ownerFilter(c, "invoices:any")
+		filter["_id"] = objID
+
+		var invoice models.Invoice
+		err = getInvoiceCollection().FindOne(ctx, filter).Decode(&invoice)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+
+		if req.PaymentStatus != invoice.PaymentStatus && !containsString(models.PaymentTransitions[invoice.PaymentStatus], req.PaymentStatus) {
+			next := models.PaymentTransitions[invoice.PaymentStatus]
+			if next == nil {
+				next = []string{}
+			}
+
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Cannot change payment status from " + invoice.PaymentStatus + " to " + req.PaymentStatus,
+				"status":  invoice.PaymentStatus,
+				"allowed": next,
+			})
+			return
+		}
 
 		update := bson.M{
 			"$set": bson.M{
-				"order_id":       invoice.OrderID,
-				"payment_method": invoice.PaymentMethod,
-				"total_amount":   invoice.TotalAmount,
-				"payment_status": invoice.PaymentStatus,
-				"updated_at":     invoice.UpdatedAt,
+				"payment_method": req.PaymentMethod,
+				"payment_status": req.PaymentStatus,
+				"updated_at":     time.Now(),
 			},
 		}
 
-		result, err := getInvoiceCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		// Only apply the change if nobody changed the status in the meantime.
+		result, err := getInvoiceCollection().UpdateOne(ctx, bson.M{"_id": objID, "payment_status": invoice.PaymentStatus}, update)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice"})
 			return
 		}
 
 		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			c.JSON(http.StatusConflict, gin.H{"error": "Payment status was changed by another request, reload and try again"})
 			return
 		}
 
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary JSON Web Key Set
+// @Description Public keys that access tokens can be verified with, identified by the kid token header. Empty when tokens are signed with an HS256 secret.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} models.JWKSResponse "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func GetJWKS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"keys": helpers.JWKS()})
+	}
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// loginThrottle describes when repeated failures lock a key out. Every failure
+// from FreeAttempts onwards locks the key for BaseLockout, doubling with each
+// further failure up to MaxLockout. Failures are forgotten after Window
+// without a new one.
+type loginThrottle struct {
+	FreeAttempts int
+	BaseLockout  time.Duration
+	MaxLockout   time.Duration
+	Window       time.Duration
+}
+
+var (
+	accountThrottle = loginThrottle{FreeAttempts: 5, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: 24 * time.Hour}
+	ipThrottle      = loginThrottle{FreeAttempts: 20, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: time.Hour}
+)
+
+func (t loginThrottle) lockout(failures int) time.Duration {
+	if failures < t.FreeAttempts {
+		return 0
+	}
+
+	lockout := t.BaseLockout
+	for i := t.FreeAttempts; i < failures && lockout < t.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > t.MaxLockout {
+		lockout = t.MaxLockout
+	}
+	return lockout
+}
+
+func getLoginAttemptCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "loginattempts")
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginLockedFor returns how long logins for any of keys remain locked, or
+// zero if none of them is locked.
+func loginLockedFor(ctx context.Context, keys ...string) (time.Duration, error) {
+	now := time.Now()
+	cursor, err := getLoginAttemptCollection().Find(ctx, bson.M{
+		"key":          bson.M{"$in": keys},
+		"locked_until": bson.M{"$gt": now},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var attempts []models.LoginAttempt
+	if err = cursor.All(ctx, &attempts); err != nil {
+		return 0, err
+	}
+
+	var remaining time.Duration
+	for _, attempt := range attempts {
+		if d := attempt.LockedUntil.Sub(now); d > remaining {
+			remaining = d
+		}
+	}
+	return remaining, nil
+}
+
+// recordLoginFailure counts a failed login against key and locks it out once
+// the throttle's free attempts are used up.
+func recordLoginFailure(ctx context.Context, key string, throttle loginThrottle) error {
+	now := time.Now()
+	var attempt models.LoginAttempt
+	err := getLoginAttemptCollection().FindOneAndUpdate(ctx,
+		bson.M{"key": key},
+		bson.M{
+			"$inc": bson.M{"failures": 1},
+			"$set": bson.M{"last_failure_at": now, "expires_at": now.Add(throttle.Window)},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&attempt)
+	if err != nil {
+		return err
+	}
+
+	lockout := throttle.lockout(attempt.Failures)
+	if lockout == 0 {
+		return nil
+	}
+
+	lockedUntil := now.Add(lockout)
+	expiresAt := now.Add(throttle.Window)
+	if lockedUntil.After(expiresAt) {
+		expiresAt = lockedUntil
+	}
+
+	_, err = getLoginAttemptCollection().UpdateOne(ctx,
+		bson.M{"key": key},
+		bson.M{"$set": bson.M{"locked_until": lockedUntil, "expires_at": expiresAt}},
+	)
+	return err
+}
+
+// clearLoginFailures forgets the failed logins recorded against key.
+func clearLoginFailures(ctx context.Context, key string) error {
+	_, err := getLoginAttemptCollection().DeleteOne(ctx, bson.M{"key": key})
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"basic-backend/database"
 	"basic-backend/models"
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -187,7 +188,7 @@ func UpdateMenu() gin.HandlerFunc {
 }
 
 // @Summary Delete Menu
-// @Description Delete a menu by ID
+// @Description Delete a menu by ID. Foods on the menu are handled according to ON_DELETE_MENU_FOODS and promotions that target it according to ON_DELETE_MENU_PROMOTIONS; by default the delete is refused with 409 while either exist.
 // @Tags Menu
 // @Accept json
 // @Produce json
@@ -196,6 +197,7 @@ func UpdateMenu() gin.HandlerFunc {
 // @Success 200 {object} models.SuccessResponse "Menu deleted successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid ID"
 // @Failure 404 {object} models.ErrorResponse "Menu not found"
+// @Failure 409 {object} models.ErrorResponse "Still referenced by other records"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /menus/{id} [delete]
 func DeleteMenu() gin.HandlerFunc {
@@ -210,13 +212,23 @@ func DeleteMenu() gin.HandlerFunc {
 			return
 		}
 
-		result, err := getMenuCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		var deleted int64
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			var err error
+			deleted, err = deleteDocuments(sessCtx, "menus", bson.M{"_id": objID})
+			return err
+		})
+		var depErr *dependentsError
+		if errors.As(err, &depErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Menu is still referenced", "dependents": depErr.Dependents})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete menu"})
 			return
 		}
 
-		if result.DeletedCount == 0 {
+		if deleted == 0 {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
 			return
 		}
@@ -2,9 +2,12 @@ package controllers
 
 import (
 	"basic-backend/database"
+	"basic-backend/helpers"
 	"basic-backend/models"
 	"context"
+	"errors"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,7 +23,7 @@ func getOrderCollection() *mongo.Collection {
 var validateOrder = validator.New()
 
 // @Summary Get All Orders
-// @Description Retrieve a list of all orders
+// @Description Retrieve a list of orders. Callers without the orders:any permission only see their own orders.
 // @Tags Order
 // @Accept json
 // @Produce json
@@ -34,7 +37,7 @@ func GetOrders() gin.HandlerFunc {
 		defer cancel()
 
 		var orders []models.Order
-		cursor, err := getOrderCollection().Find(ctx, bson.M{})
+		cursor, err := getOrderCollection().Find(ctx, ownerFilter(c, "orders:any"))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching orders"})
 			return
@@ -51,15 +54,16 @@ func GetOrders() gin.HandlerFunc {
 }
 
 // @Summary Get Order by ID
-// @Description Retrieve a specific order by its ID
+// @Description Retrieve a specific order with its items, each line total and the order's subtotal, tax, discount and total, all computed by the server
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Order ID"
-// @Success 200 {object} models.OrderResponse "Order details"
+// @Success 200 {object} models.OrderDetail "Order details"
 // @Failure 400 {object} models.ErrorResponse "Invalid ID"
 // @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orders/{id} [get]
 func GetOrder() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -73,26 +77,33 @@ func GetOrder() gin.HandlerFunc {
 			return
 		}
 
-		var order models.Order
-		err = getOrderCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&order)
-		if err != nil {
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = objID
+
+		detail, err := loadOrderDetail(ctx, filter)
+		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 			return
 		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
 
-		c.JSON(http.StatusOK, order)
+		c.JSON(http.StatusOK, detail)
 	}
 }
 
 // @Summary Create Order
-// @Description Create a new order
+// @Description Create a new order, optionally together with its items. The order and all items are inserted in one transaction, so either everything is created or nothing is. New orders always start as pending; use /orders/{id}/status to move them on. Creating items requires orderitems:create.
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param order body models.OrderCreateRequest true "Order details"
-// @Success 201 {object} models.OrderResponse "Order created successfully"
+// @Success 201 {object} models.OrderDetailResponse "Order created successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 403 {object} models.ErrorResponse "Missing orderitems:create permission"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orders [post]
 func CreateOrder() gin.HandlerFunc {
@@ -100,48 +111,191 @@ func CreateOrder() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var order models.Order
-		if err := c.BindJSON(&order); err != nil {
+		var req models.OrderCreateRequest
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		validationErr := validateOrder.Struct(order)
+		validationErr := validateOrder.Struct(req)
 		if validationErr != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
 			return
 		}
 
-		order.CreatedAt = time.Now()
-		order.UpdatedAt = time.Now()
-		order.OrderDate = time.Now()
-		order.ID = primitive.NewObjectID()
+		if len(req.Items) > 0 && !helpers.HasPermission(c.GetStringSlice("permissions"), "orderitems:create") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission orderitems:create required"})
+			return
+		}
+
+		if req.TableID != "" {
+			exists, err := referenceExists(ctx, "tables", req.TableID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking table"})
+				return
+			}
+
+			if !exists {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Table not found"})
+				return
+			}
+		}
+
+		serviceType := req.ServiceType
+		if serviceType == "" {
+			serviceType = models.ServiceTypeDineIn
+		}
+
+		now := time.Now()
+		order := models.Order{
+			ID:               primitive.NewObjectID(),
+			TableID:          req.TableID,
+			ServiceType:      serviceType,
+			GuestCount:       req.GuestCount,
+			OrderDate:        now,
+			Status:           models.OrderStatusPending,
+			UserID:           c.GetString("uid"),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+			StatusTimestamps: map[string]time.Time{models.OrderStatusPending: now},
+		}
 
-		result, err := getOrderCollection().InsertOne(ctx, order)
+		validationErr = validateOrder.Struct(order)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		items, err := newOrderItems(ctx, c, order.ID.Hex(), req.Items)
+		var lineErr *orderLineError
+		if errors.As(err, &lineErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": lineErr.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching foods"})
+			return
+		}
+
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			if _, err := getOrderCollection().InsertOne(sessCtx, order); err != nil {
+				return err
+			}
+			return insertOrderItems(sessCtx, items)
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order"})
 			return
 		}
 
+		detail, err := loadOrderDetail(ctx, bson.M{"_id": order.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
+
 		c.JSON(http.StatusCreated, gin.H{
 			"message": "Order created successfully",
-			"id":      result.InsertedID,
-			"order":   order,
+			"id":      order.ID,
+			"order":   detail,
 		})
 	}
 }
 
+// orderDetailPipeline joins the orders matching filter with their items, the
+// items' foods and the foods' menus, and computes each line total and the
+// subtotal. Amounts are Decimal128, so the arithmetic is exact. Items created
+// before names and tax classes were copied from the food fall back to the
+// food's current ones.
+func orderDetailPipeline(filter bson.M) []bson.M {
+	return []bson.M{
+		{"$match": filter},
+		{"$lookup": bson.M{
+			"from": "orderitems",
+			"let":  bson.M{"order_id": bson.M{"$toString": "$_id"}},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$order_id", "$$order_id"}}}},
+				{"$sort": bson.M{"created_at": 1}},
+				{"$lookup": bson.M{
+					"from": "foods",
+					"let": bson.M{"food_id": bson.M{"$convert": bson.M{
+						"input": "$food_id", "to": "objectId", "onError": nil, "onNull": nil,
+					}}},
+					"pipeline": []bson.M{
+						{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$food_id"}}}},
+						{"$lookup": bson.M{
+							"from": "menus",
+							"let": bson.M{"menu_id": bson.M{"$convert": bson.M{
+								"input": "$menu_id", "to": "objectId", "onError": nil, "onNull": nil,
+							}}},
+							"pipeline": []bson.M{
+								{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$menu_id"}}}},
+								{"$project": bson.M{"category": 1}},
+							},
+							"as": "menu",
+						}},
+						{"$project": bson.M{
+							"name":         1,
+							"tax_class_id": 1,
+							"menu_id":      1,
+							"category":     bson.M{"$arrayElemAt": bson.A{"$menu.category", 0}},
+						}},
+					},
+					"as": "food",
+				}},
+				{"$set": bson.M{
+					"name":         bson.M{"$ifNull": bson.A{"$name", bson.M{"$arrayElemAt": bson.A{"$food.name", 0}}}},
+					"tax_class_id": bson.M{"$ifNull": bson.A{"$tax_class_id", bson.M{"$arrayElemAt": bson.A{"$food.tax_class_id", 0}}}},
+					"menu_id":      bson.M{"$arrayElemAt": bson.A{"$food.menu_id", 0}},
+					"category":     bson.M{"$arrayElemAt": bson.A{"$food.category", 0}},
+					"line_total":   bson.M{"$multiply": bson.A{"$quantity", "$unit_price"}},
+				}},
+				{"$unset": "food"},
+			},
+			"as": "items",
+		}},
+		{"$set": bson.M{"totals.subtotal": bson.M{"$sum": "$items.line_total"}}},
+	}
+}
+
+// loadOrderDetail returns the order matching filter with its items and
+// totals, including discounts and taxes, or mongo.ErrNoDocuments if there is none.
+func loadOrderDetail(ctx context.Context, filter bson.M) (models.OrderDetail, error) {
+	var detail models.OrderDetail
+
+	cursor, err := getOrderCollection().Aggregate(ctx, orderDetailPipeline(filter))
+	if err != nil {
+		return detail, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return detail, err
+		}
+		return detail, mongo.ErrNoDocuments
+	}
+
+	if err := cursor.Decode(&detail); err != nil {
+		return detail, err
+	}
+
+	err = completeTotals(ctx, &detail)
+	return detail, err
+}
+
 // @Summary Update Order
-// @Description Update an existing order
+// @Description Update an existing order's table, service type and guest count. Takeaway orders need no table, and billed or cancelled orders cannot be changed. The status is not changed here; use /orders/{id}/status.
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Order ID"
-// @Param order body models.OrderCreateRequest true "Updated order details"
+// @Param order body models.OrderUpdateRequest true "Updated order details"
 // @Success 200 {object} models.SuccessResponse "Order updated successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request"
 // @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Order is billed or cancelled"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orders/{id} [put]
 func UpdateOrder() gin.HandlerFunc {
@@ -156,30 +310,72 @@ func UpdateOrder() gin.HandlerFunc {
 			return
 		}
 
-		var order models.Order
-		if err := c.BindJSON(&order); err != nil {
+		var req models.OrderUpdateRequest
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		order.UpdatedAt = time.Now()
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if req.TableID != "" {
+			exists, err := referenceExists(ctx, "tables", req.TableID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking table"})
+				return
+			}
+
+			if !exists {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Table not found"})
+				return
+			}
+		}
+
+		serviceType := req.ServiceType
+		if serviceType == "" {
+			serviceType = models.ServiceTypeDineIn
+		}
 
 		update := bson.M{
 			"$set": bson.M{
-				"table_id":   order.TableID,
-				"status":     order.Status,
-				"updated_at": order.UpdatedAt,
+				"table_id":     req.TableID,
+				"service_type": serviceType,
+				"guest_count":  req.GuestCount,
+				"updated_at":   time.Now(),
 			},
 		}
 
-		result, err := getOrderCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = objID
+
+		openFilter := bson.M{"status": bson.M{"$in": openOrderStatuses}}
+		for key, value := range filter {
+			openFilter[key] = value
+		}
+
+		result, err := getOrderCollection().UpdateOne(ctx, openFilter, update)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order"})
 			return
 		}
 
 		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			count, err := getOrderCollection().CountDocuments(ctx, filter)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking order"})
+				return
+			}
+
+			if count == 0 {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+				return
+			}
+
+			c.JSON(http.StatusConflict, gin.H{"error": "A billed or cancelled order cannot be changed"})
 			return
 		}
 
@@ -188,7 +384,7 @@ func UpdateOrder() gin.HandlerFunc {
 }
 
 // @Summary Delete Order
-// @Description Delete an order by ID
+// @Description Delete an order by ID. Its items are deleted with it and invoices for it are handled according to ON_DELETE_ORDER_INVOICES; by default the delete is refused with 409 while invoices exist.
 // @Tags Order
 // @Accept json
 // @Produce json
@@ -197,6 +393,7 @@ func UpdateOrder() gin.HandlerFunc {
 // @Success 200 {object} models.SuccessResponse "Order deleted successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid ID"
 // @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Still referenced by other records"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orders/{id} [delete]
 func DeleteOrder() gin.HandlerFunc {
@@ -211,13 +408,26 @@ func DeleteOrder() gin.HandlerFunc {
 			return
 		}
 
-		result, err := getOrderCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = objID
+
+		var deleted int64
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			var err error
+			deleted, err = deleteDocuments(sessCtx, "orders", filter)
+			return err
+		})
+		var depErr *dependentsError
+		if errors.As(err, &depErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Order is still referenced", "dependents": depErr.Dependents})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order"})
 			return
 		}
 
-		if result.DeletedCount == 0 {
+		if deleted == 0 {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 			return
 		}
@@ -226,3 +436,108 @@ func DeleteOrder() gin.HandlerFunc {
 	}
 }
 
+// @Summary Change Order Status
+// @Description Move an order to a new status. Allowed transitions are pending to preparing or cancelled, preparing to ready or cancelled, and ready to delivered or cancelled; cancelled is final and delivered orders only move on to billed when they are invoiced through /orders/{id}/invoice. Starting and finishing preparation requires orders:prepare, delivering requires orders:deliver, cancelling a pending order requires orders:cancel and cancelling after preparation has started requires orders:void. The time of each transition is recorded in status_timestamps.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body models.OrderStatusRequest true "New status"
+// @Success 200 {object} models.OrderResponse "Order status changed"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 403 {object} models.ErrorResponse "Missing permission for this transition"
+// @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Illegal status transition"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /orders/{id}/status [post]
+func UpdateOrderStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+			return
+		}
+
+		var req models.OrderStatusRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validateOrder.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = objID
+
+		var order models.Order
+		err = getOrderCollection().FindOne(ctx, filter).Decode(&order)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+
+		permission, allowed := models.OrderTransitions[order.Status][req.Status]
+		if !allowed {
+			next := []string{}
+			for status := range models.OrderTransitions[order.Status] {
+				next = append(next, status)
+			}
+			sort.Strings(next)
+
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Cannot change order status from " + order.Status + " to " + req.Status,
+				"status":  order.Status,
+				"allowed": next,
+			})
+			return
+		}
+
+		if !helpers.HasPermission(c.GetStringSlice("permissions"), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission " + permission + " required"})
+			return
+		}
+
+		// Only apply the change if nobody moved the order on in the meantime.
+		now := time.Now()
+		update := bson.M{
+			"$set": bson.M{
+				"status":                          req.Status,
+				"status_timestamps." + req.Status: now,
+				"updated_at":                      now,
+			},
+		}
+
+		result, err := getOrderCollection().UpdateOne(ctx, bson.M{"_id": objID, "status": order.Status}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order status"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Order status was changed by another request, reload and try again"})
+			return
+		}
+
+		order.Status = req.Status
+		order.UpdatedAt = now
+		if order.StatusTimestamps == nil {
+			order.StatusTimestamps = map[string]time.Time{}
+		}
+		order.StatusTimestamps[req.Status] = now
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Order status changed to " + req.Status,
+			"id":      order.ID,
+			"order":   order,
+		})
+	}
+}
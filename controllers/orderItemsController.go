@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"basic-backend/database"
+	"basic-backend/helpers"
 	"basic-backend/models"
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -19,8 +21,104 @@ func getOrderItemCollection() *mongo.Collection {
 }
 var validateOrderItem = validator.New()
 
+// errOrderItemNotInOrder aborts a bulk edit that references an item which does
+// not belong to the order being edited.
+var errOrderItemNotInOrder = errors.New("order item not found in this order")
+
+// errOrderClosed refuses to change the items of an order that has been billed
+// or cancelled.
+var errOrderClosed = errors.New("order is closed")
+
+// openOrderStatuses are the statuses in which an order's items, table, guest
+// count and coupons can still be changed.
+var openOrderStatuses = []string{
+	models.OrderStatusPending, models.OrderStatusPreparing,
+	models.OrderStatusReady, models.OrderStatusDelivered,
+}
+
+// touchOpenOrders marks the orders with the given IDs as updated, failing with
+// errOrderClosed unless all of them are open. It must run in the transaction
+// that changes their items: billing writes the order as well, so an order
+// cannot be billed while its items are being changed.
+func touchOpenOrders(sessCtx mongo.SessionContext, orderIDs ...string) error {
+	for _, orderID := range orderIDs {
+		objID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			return errOrderClosed
+		}
+
+		result, err := getOrderCollection().UpdateOne(sessCtx,
+			bson.M{"_id": objID, "status": bson.M{"$in": openOrderStatuses}},
+			bson.M{"$set": bson.M{"updated_at": time.Now()}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errOrderClosed
+		}
+	}
+	return nil
+}
+
+// newOrderItems builds the items to add to an order, copying the name and
+// price of each food. Unknown or unavailable foods and items that break the
+// rules declared on models.OrderItem are rejected with an orderLineError.
+func newOrderItems(ctx context.Context, c *gin.Context, orderID string, lines []models.OrderLineRequest) ([]models.OrderItem, error) {
+	foodIDs := make([]string, len(lines))
+	for i, line := range lines {
+		foodIDs[i] = line.FoodID
+	}
+
+	foods, err := loadFoods(ctx, foodIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	items := make([]models.OrderItem, 0, len(lines))
+	for _, line := range lines {
+		food, err := orderableFood(foods, line.FoodID)
+		if err != nil {
+			return nil, err
+		}
+
+		item := models.OrderItem{
+			ID:         primitive.NewObjectID(),
+			OrderID:    orderID,
+			FoodID:     line.FoodID,
+			Name:       food.Name,
+			TaxClassID: food.TaxClassID,
+			Quantity:   line.Quantity,
+			UnitPrice:  linePrice(c, food, line.UnitPrice),
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+
+		if err := validateOrderItem.Struct(item); err != nil {
+			return nil, &orderLineError{err.Error()}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func insertOrderItems(ctx context.Context, items []models.OrderItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(items))
+	for i, item := range items {
+		documents[i] = item
+	}
+
+	_, err := getOrderItemCollection().InsertMany(ctx, documents)
+	return err
+}
+
 // @Summary Get Order Items
-// @Description Retrieve order items, optionally filtered by order ID
+// @Description Retrieve order items, optionally filtered by order ID. Callers without the orders:any permission only see items of their own orders.
 // @Tags OrderItem
 // @Accept json
 // @Produce json
@@ -35,9 +133,22 @@ func GetOrderItems() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
+		orderIDs, err := ownedOrderIDs(ctx, c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching orders"})
+			return
+		}
+
 		filter := bson.M{}
+		if orderIDs != nil {
+			filter["order_id"] = bson.M{"$in": orderIDs}
+		}
 		if orderID != "" {
 			filter["order_id"] = orderID
+			if orderIDs != nil && !containsString(orderIDs, orderID) {
+				c.JSON(http.StatusOK, []models.OrderItem{})
+				return
+			}
 		}
 
 		var orderItems []models.OrderItem
@@ -87,6 +198,17 @@ func GetOrderItem() gin.HandlerFunc {
 			return
 		}
 
+		canAccess, err := canAccessOrder(ctx, c, orderItem.OrderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
+
+		if !canAccess {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
+			return
+		}
+
 		c.JSON(http.StatusOK, orderItem)
 	}
 }
@@ -100,6 +222,8 @@ func GetOrderItem() gin.HandlerFunc {
 // @Param orderitem body models.OrderItemCreateRequest true "Order item details"
 // @Success 201 {object} models.OrderItemResponse "Order item created successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Order is billed or cancelled"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orderitems [post]
 func CreateOrderItem() gin.HandlerFunc {
@@ -107,23 +231,62 @@ func CreateOrderItem() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var orderItem models.OrderItem
-		if err := c.BindJSON(&orderItem); err != nil {
+		var req models.OrderItemCreateRequest
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		validationErr := validateOrderItem.Struct(orderItem)
+		validationErr := validateOrderItem.Struct(req)
 		if validationErr != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
 			return
 		}
 
-		orderItem.CreatedAt = time.Now()
-		orderItem.UpdatedAt = time.Now()
-		orderItem.ID = primitive.NewObjectID()
+		// canAccessOrder does not look the order up for staff, so check that
+		// it exists before its status is checked.
+		exists, err := referenceExists(ctx, "orders", req.OrderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
 
-		result, err := getOrderItemCollection().InsertOne(ctx, orderItem)
+		canAccess, err := canAccessOrder(ctx, c, req.OrderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
+
+		if !exists || !canAccess {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+
+		items, err := newOrderItems(ctx, c, req.OrderID, []models.OrderLineRequest{
+			{FoodID: req.FoodID, Quantity: req.Quantity, UnitPrice: req.UnitPrice},
+		})
+		var lineErr *orderLineError
+		if errors.As(err, &lineErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": lineErr.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching foods"})
+			return
+		}
+		orderItem := items[0]
+
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			if err := touchOpenOrders(sessCtx, req.OrderID); err != nil {
+				return err
+			}
+			_, err := getOrderItemCollection().InsertOne(sessCtx, orderItem)
+			return err
+		})
+		if errors.Is(err, errOrderClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Items of a billed or cancelled order cannot be changed"})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order item"})
 			return
@@ -131,7 +294,7 @@ func CreateOrderItem() gin.HandlerFunc {
 
 		c.JSON(http.StatusCreated, gin.H{
 			"message":    "Order item created successfully",
-			"id":         result.InsertedID,
+			"id":         orderItem.ID,
 			"order_item": orderItem,
 		})
 	}
@@ -147,7 +310,8 @@ func CreateOrderItem() gin.HandlerFunc {
 // @Param orderitem body models.OrderItemCreateRequest true "Updated order item details"
 // @Success 200 {object} models.SuccessResponse "Order item updated successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request"
-// @Failure 404 {object} models.ErrorResponse "Order item not found"
+// @Failure 404 {object} models.ErrorResponse "Order item or order not found"
+// @Failure 409 {object} models.ErrorResponse "Order is billed or cancelled"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orderitems/{id} [put]
 func UpdateOrderItem() gin.HandlerFunc {
@@ -162,34 +326,129 @@ func UpdateOrderItem() gin.HandlerFunc {
 			return
 		}
 
-		var orderItem models.OrderItem
-		if err := c.BindJSON(&orderItem); err != nil {
+		var req models.OrderItemCreateRequest
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
+		validationErr := validateOrderItem.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		canAccessItem, err := canAccessOrderItem(ctx, c, objID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order item"})
+			return
+		}
+
+		if !canAccessItem {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
+			return
+		}
+
+		// canAccessOrder does not look the order up for staff, so check that
+		// it exists before its status is checked.
+		exists, err := referenceExists(ctx, "orders", req.OrderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
+
+		canAccess, err := canAccessOrder(ctx, c, req.OrderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+			return
+		}
+
+		if !exists || !canAccess {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+
+		var orderItem models.OrderItem
+		err = getOrderItemCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&orderItem)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
+			return
+		}
+
+		// Keep the price the item was ordered at unless the food changes.
+		if req.FoodID != orderItem.FoodID {
+			foods, err := loadFoods(ctx, []string{req.FoodID})
+			var lineErr *orderLineError
+			if errors.As(err, &lineErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": lineErr.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching foods"})
+				return
+			}
+
+			food, err := orderableFood(foods, req.FoodID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
+			orderItem.Name = food.Name
+			orderItem.TaxClassID = food.TaxClassID
+			orderItem.UnitPrice = food.Price
+		}
+
+		if req.UnitPrice.IsPositive() && canOverridePrice(c) {
+			orderItem.UnitPrice = req.UnitPrice
+		}
+
 		orderItem.UpdatedAt = time.Now()
 
 		update := bson.M{
 			"$set": bson.M{
-				"order_id":   orderItem.OrderID,
-				"food_id":    orderItem.FoodID,
-				"quantity":   orderItem.Quantity,
-				"unit_price": orderItem.UnitPrice,
-				"updated_at": orderItem.UpdatedAt,
+				"order_id":     req.OrderID,
+				"food_id":      req.FoodID,
+				"name":         orderItem.Name,
+				"tax_class_id": orderItem.TaxClassID,
+				"quantity":     req.Quantity,
+				"unit_price":   orderItem.UnitPrice,
+				"updated_at":   orderItem.UpdatedAt,
 			},
 		}
 
-		result, err := getOrderItemCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order item"})
-			return
+		// Both the order the item is in and the one it moves to must be open.
+		orderIDs := []string{orderItem.OrderID}
+		if req.OrderID != orderItem.OrderID {
+			orderIDs = append(orderIDs, req.OrderID)
 		}
 
-		if result.MatchedCount == 0 {
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			if err := touchOpenOrders(sessCtx, orderIDs...); err != nil {
+				return err
+			}
+
+			result, err := getOrderItemCollection().UpdateOne(sessCtx, bson.M{"_id": objID, "order_id": orderItem.OrderID}, update)
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return mongo.ErrNoDocuments
+			}
+			return nil
+		})
+		if errors.Is(err, errOrderClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Items of a billed or cancelled order cannot be changed"})
+			return
+		}
+		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
 			return
 		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order item"})
+			return
+		}
 
 		c.JSON(http.StatusOK, gin.H{"message": "Order item updated successfully"})
 	}
@@ -205,6 +464,7 @@ func UpdateOrderItem() gin.HandlerFunc {
 // @Success 200 {object} models.SuccessResponse "Order item deleted successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid ID"
 // @Failure 404 {object} models.ErrorResponse "Order item not found"
+// @Failure 409 {object} models.ErrorResponse "Order is billed or cancelled"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /orderitems/{id} [delete]
 func DeleteOrderItem() gin.HandlerFunc {
@@ -219,17 +479,226 @@ func DeleteOrderItem() gin.HandlerFunc {
 			return
 		}
 
-		result, err := getOrderItemCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		canAccessItem, err := canAccessOrderItem(ctx, c, objID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order item"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order item"})
 			return
 		}
 
-		if result.DeletedCount == 0 {
+		if !canAccessItem {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
 			return
 		}
 
+		var orderItem models.OrderItem
+		err = getOrderItemCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&orderItem)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
+			return
+		}
+
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			if err := touchOpenOrders(sessCtx, orderItem.OrderID); err != nil {
+				return err
+			}
+
+			result, err := getOrderItemCollection().DeleteOne(sessCtx, bson.M{"_id": objID, "order_id": orderItem.OrderID})
+			if err != nil {
+				return err
+			}
+			if result.DeletedCount == 0 {
+				return mongo.ErrNoDocuments
+			}
+			return nil
+		})
+		if errors.Is(err, errOrderClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Items of a billed or cancelled order cannot be changed"})
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order item"})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{"message": "Order item deleted successfully"})
 	}
 }
+
+// @Summary Bulk Edit Order Items
+// @Description Add, change and remove several items of an order in one transaction: if any change fails, none is applied. Adding requires orderitems:create and removing requires orderitems:delete. Returns the order with its resulting items.
+// @Tags OrderItem
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body models.OrderItemsBulkRequest true "Item changes"
+// @Success 200 {object} models.OrderDetailResponse "Order items updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 403 {object} models.ErrorResponse "Missing orderitems:create or orderitems:delete permission"
+// @Failure 404 {object} models.ErrorResponse "Order or order item not found"
+// @Failure 409 {object} models.ErrorResponse "Order is billed or cancelled"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /orders/{id}/items [patch]
+func UpdateOrderItems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+			return
+		}
+
+		var req models.OrderItemsBulkRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validateOrderItem.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if len(req.Add) == 0 && len(req.Update) == 0 && len(req.Remove) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No item changes given"})
+			return
+		}
+
+		permissions := c.GetStringSlice("permissions")
+		if len(req.Add) > 0 && !helpers.HasPermission(permissions, "orderitems:create") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission orderitems:create required"})
+			return
+		}
+
+		if len(req.Remove) > 0 && !helpers.HasPermission(permissions, "orderitems:delete") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission orderitems:delete required"})
+			return
+		}
+
+		// Each existing item may only be touched once per request.
+		seen := map[primitive.ObjectID]bool{}
+		parseItemID := func(id string) (primitive.ObjectID, bool) {
+			itemID, err := primitive.ObjectIDFromHex(id)
+			if err != nil || seen[itemID] {
+				return itemID, false
+			}
+			seen[itemID] = true
+			return itemID, true
+		}
+
+		updateIDs := make([]primitive.ObjectID, len(req.Update))
+		for i, line := range req.Update {
+			itemID, ok := parseItemID(line.ID)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or repeated order item ID " + line.ID})
+				return
+			}
+			updateIDs[i] = itemID
+		}
+
+		removeIDs := make([]primitive.ObjectID, len(req.Remove))
+		for i, id := range req.Remove {
+			itemID, ok := parseItemID(id)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or repeated order item ID " + id})
+				return
+			}
+			removeIDs[i] = itemID
+		}
+
+		filter := ownerFilter(c, "orders:any")
+		filter["_id"] = objID
+
+		var order models.Order
+		err = getOrderCollection().FindOne(ctx, filter).Decode(&order)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+
+		added, err := newOrderItems(ctx, c, orderID, req.Add)
+		var lineErr *orderLineError
+		if errors.As(err, &lineErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": lineErr.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching foods"})
+			return
+		}
+
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			if err := touchOpenOrders(sessCtx, orderID); err != nil {
+				return err
+			}
+
+			if err := insertOrderItems(sessCtx, added); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			overridePrice := canOverridePrice(c)
+			for i, line := range req.Update {
+				set := bson.M{
+					"quantity":   line.Quantity,
+					"updated_at": now,
+				}
+				if line.UnitPrice.IsPositive() && overridePrice {
+					set["unit_price"] = line.UnitPrice
+				}
+				update := bson.M{"$set": set}
+
+				result, err := getOrderItemCollection().UpdateOne(sessCtx, bson.M{"_id": updateIDs[i], "order_id": orderID}, update)
+				if err != nil {
+					return err
+				}
+				if result.MatchedCount == 0 {
+					return errOrderItemNotInOrder
+				}
+			}
+
+			if len(removeIDs) > 0 {
+				result, err := getOrderItemCollection().DeleteMany(sessCtx, bson.M{"_id": bson.M{"$in": removeIDs}, "order_id": orderID})
+				if err != nil {
+					return err
+				}
+				if result.DeletedCount != int64(len(removeIDs)) {
+					return errOrderItemNotInOrder
+				}
+			}
+			return nil
+		})
+		if errors.Is(err, errOrderItemNotInOrder) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order item not found in this order"})
+			return
+		}
+		if errors.Is(err, errOrderClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Items of a billed or cancelled order cannot be changed"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order items"})
+			return
+		}
+
+		detail, err := loadOrderDetail(ctx, bson.M{"_id": order.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order items"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Order items updated successfully",
+			"id":      order.ID,
+			"order":   detail,
+		})
+	}
+}
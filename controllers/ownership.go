@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ownerFilter returns a filter restricting a query to documents owned by the
+// caller, or an empty filter when the caller's role grants anyPermission.
+func ownerFilter(c *gin.Context, anyPermission string) bson.M {
+	if helpers.HasPermission(c.GetStringSlice("permissions"), anyPermission) {
+		return bson.M{}
+	}
+	return bson.M{"user_id": c.GetString("uid")}
+}
+
+// ownedOrderIDs returns the IDs of the orders the caller may access, or nil
+// when the caller may access every order.
+func ownedOrderIDs(ctx context.Context, c *gin.Context) ([]string, error) {
+	filter := ownerFilter(c, "orders:any")
+	if len(filter) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := getOrderCollection().Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orders []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID.Hex())
+	}
+	return ids, nil
+}
+
+// canAccessOrder reports whether the caller may access the order with the
+// given hex ID.
+func canAccessOrder(ctx context.Context, c *gin.Context, orderID string) (bool, error) {
+	filter := ownerFilter(c, "orders:any")
+	if len(filter) == 0 {
+		return true, nil
+	}
+
+	objID, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return false, nil
+	}
+	filter["_id"] = objID
+
+	count, err := getOrderCollection().CountDocuments(ctx, filter)
+	return count > 0, err
+}
+
+// canAccessOrderItem reports whether the caller may access the order item with
+// the given ID. A missing item is reported as not accessible.
+func canAccessOrderItem(ctx context.Context, c *gin.Context, orderItemID primitive.ObjectID) (bool, error) {
+	if len(ownerFilter(c, "orders:any")) == 0 {
+		return true, nil
+	}
+
+	var orderItem models.OrderItem
+	err := getOrderItemCollection().FindOne(ctx, bson.M{"_id": orderItemID}).Decode(&orderItem)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return canAccessOrder(ctx, c, orderItem.OrderID)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"basic-backend/mailer"
+	"basic-backend/models"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const passwordResetTTL = time.Hour
+
+// @Summary Forgot Password
+// @Description Email a single-use password reset link to the account. The response is the same whether or not the email is registered.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} models.SuccessResponse "Reset instructions sent if the account exists"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/password/forgot [post]
+func ForgotPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.ForgotPasswordRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		response := gin.H{"message": "If the account exists, password reset instructions have been sent"}
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
+		if err == mongo.ErrNoDocuments || (err == nil && user.Disabled) {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+			return
+		}
+
+		token, err := issueUserToken(ctx, user.ID, models.UserTokenPasswordReset, passwordResetTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating reset token"})
+			return
+		}
+
+		link := appBaseURL() + "/reset-password?token=" + url.QueryEscape(token)
+		err = mailer.Send(ctx, mailer.Message{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Body: "Hi " + user.FirstName + ",\n\n" +
+				"Use the link below to choose a new password. It expires in one hour and can only be used once.\n\n" +
+				link + "\n\n" +
+				"If you did not request a password reset you can ignore this email.\n",
+		})
+		if err != nil {
+			log.Println("Failed to send password reset email:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sending reset email"})
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary Reset Password
+// @Description Set a new password using a token from a password reset email. All existing sessions are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} models.SuccessResponse "Password reset successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or invalid/expired token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/password/reset [post]
+func ResetPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.ResetPasswordRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		record, err := consumeUserToken(ctx, req.Token, models.UserTokenPasswordReset)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking reset token"})
+			return
+		}
+
+		hashedPassword, err := helpers.HashPassword(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error hashing password"})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"password":  hashedPassword,
+				"updatedat": time.Now(),
+			},
+		}
+
+		result, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": record.UserID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, record.UserID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+	}
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"basic-backend/money"
+	"context"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Validate amounts of money by their minor units, so that rules such as
+// required and gt=0 apply to money.Money fields.
+func init() {
+	for _, v := range []*validator.Validate{validate, validateOrder, validateOrderItem} {
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			return field.Interface().(money.Money).Amount
+		}, money.Money{})
+	}
+}
+
+// orderLineError rejects an order line because of what the client sent, such
+// as an unknown or unavailable food. Handlers answer it with 400.
+type orderLineError struct {
+	message string
+}
+
+func (e *orderLineError) Error() string {
+	return e.message
+}
+
+// canOverridePrice reports whether the caller may set an item's unit price
+// instead of taking it from the food.
+func canOverridePrice(c *gin.Context) bool {
+	return helpers.HasPermission(c.GetStringSlice("permissions"), "orderitems:price")
+}
+
+// loadFoods returns the foods with the given IDs keyed by ID. Foods that do
+// not exist are missing from the map.
+func loadFoods(ctx context.Context, ids []string) (map[string]models.Food, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, &orderLineError{"Invalid food ID " + id}
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	foods := map[string]models.Food{}
+	if len(objIDs) == 0 {
+		return foods, nil
+	}
+
+	cursor, err := getFoodCollection().Find(ctx, bson.M{"_id": bson.M{"$in": objIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []models.Food
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	for _, food := range found {
+		foods[food.ID.Hex()] = food
+	}
+	return foods, nil
+}
+
+// orderableFood returns the food with the given ID from foods, or an
+// orderLineError if it does not exist or is not available.
+func orderableFood(foods map[string]models.Food, id string) (models.Food, error) {
+	food, ok := foods[id]
+	if !ok {
+		return food, &orderLineError{"Food " + id + " not found"}
+	}
+	if !food.Available {
+		return food, &orderLineError{"Food " + food.Name + " is not available"}
+	}
+	return food, nil
+}
+
+// linePrice is the unit price of an item of food. The price the client sent
+// is used only if the caller may override prices.
+func linePrice(c *gin.Context, food models.Food, requested money.Money) money.Money {
+	if requested.IsPositive() && canOverridePrice(c) {
+		return requested
+	}
+	return food.Price
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// revokeOtherSessions signs the user out of every session except keepFamilyID,
+// the one making the request.
+func revokeOtherSessions(ctx context.Context, userID primitive.ObjectID, keepFamilyID string) error {
+	keepID, _ := primitive.ObjectIDFromHex(keepFamilyID)
+
+	cursor, err := getSessionCollection().Find(ctx, bson.M{
+		"user_id": userID,
+		"revoked": false,
+		"_id":     bson.M{"$ne": keepID},
+	})
+	if err != nil {
+		return err
+	}
+
+	var sessions []models.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if _, err := revokeSession(ctx, bson.M{"_id": session.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// @Summary Update Current User
+// @Description Change the authenticated user's first name, last name or phone. Omitted fields are left unchanged; the same rules as on signup apply.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param profile body models.ProfileUpdateRequest true "Fields to change"
+// @Success 200 {object} models.UserSummary "Updated user profile"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or validation error"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/user [patch]
+func UpdateProfile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.ProfileUpdateRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": c.GetString("email")}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		// Apply the changes to the user and validate only the changed fields
+		// against the rules declared on models.User.
+		set := bson.M{}
+		var fields []string
+		if req.FirstName != nil {
+			user.FirstName = *req.FirstName
+			set["firstname"] = user.FirstName
+			fields = append(fields, "FirstName")
+		}
+		if req.LastName != nil {
+			user.LastName = *req.LastName
+			set["lastname"] = user.LastName
+			fields = append(fields, "LastName")
+		}
+		if req.Phone != nil {
+			user.Phone = *req.Phone
+			set["phone"] = user.Phone
+			fields = append(fields, "Phone")
+		}
+
+		if len(fields) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
+
+		validationErr := validate.StructPartial(user, fields...)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		user.UpdatedAt = time.Now()
+		set["updatedat"] = user.UpdatedAt
+
+		_, err = getUserCollection().UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": set})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+
+		user.Password = ""
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// @Summary Change Password
+// @Description Change the authenticated user's password. The current password is required, the new one follows the same rules as on signup, and every other session is signed out.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} models.SuccessResponse "Password changed successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or validation error"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token, or wrong current password"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/user/password [post]
+func ChangePassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.ChangePasswordRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": c.GetString("email")}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if !helpers.VerifyPassword(user.Password, req.CurrentPassword) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+
+		user.Password = req.NewPassword
+		validationErr = validate.StructPartial(user, "Password")
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		hashedPassword, err := helpers.HashPassword(req.NewPassword)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error hashing password"})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"password":  hashedPassword,
+				"updatedat": time.Now(),
+			},
+		}
+
+		_, err = getUserCollection().UpdateOne(ctx, bson.M{"_id": user.ID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+			return
+		}
+
+		if err := revokeOtherSessions(ctx, user.ID, c.GetString("family_id")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+	}
+}
@@ -0,0 +1,469 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func getPromotionCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "promotions")
+}
+
+// normalizeCouponCode makes coupon codes case-insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// promotionRequestError checks the fields of req that depend on each other
+// and returns a message describing the first problem, or "" if there is none.
+func promotionRequestError(req models.PromotionRequest) string {
+	switch req.Type {
+	case models.PromotionTypePercentage:
+		if req.Percent <= 0 {
+			return "Percentage promotions need a percent above 0"
+		}
+	case models.PromotionTypeFixed:
+		if !req.Amount.IsPositive() {
+			return "Fixed promotions need an amount above 0"
+		}
+	case models.PromotionTypeBuyXGetY:
+		if req.BuyQuantity < 1 || req.GetQuantity < 1 {
+			return "Buy-X-get-Y promotions need a buy_quantity and get_quantity of at least 1"
+		}
+	}
+
+	if (req.StartTime == "") != (req.EndTime == "") {
+		return "start_time and end_time must be given together"
+	}
+	if req.StartTime != "" && req.StartTime == req.EndTime {
+		return "start_time and end_time must differ"
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return "ends_at must be after starts_at"
+	}
+	return ""
+}
+
+// @Summary Get All Promotions
+// @Description Retrieve every promotion, including coupon codes and how often they were used (requires promotions:manage)
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Promotion "List of promotions"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /promotions [get]
+func GetPromotions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var promotions []models.Promotion
+		cursor, err := getPromotionCollection().Find(ctx, bson.M{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching promotions"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &promotions); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding promotions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, promotions)
+	}
+}
+
+// @Summary Get Promotion by ID
+// @Description Retrieve a specific promotion by its ID (requires promotions:manage)
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} models.Promotion "Promotion details"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Promotion not found"
+// @Router /promotions/{id} [get]
+func GetPromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		promotionID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(promotionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+			return
+		}
+
+		var promotion models.Promotion
+		err = getPromotionCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&promotion)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Promotion not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, promotion)
+	}
+}
+
+// @Summary Create Promotion
+// @Description Define a percentage, fixed or buy-X-get-Y promotion (requires promotions:manage). Promotions without a code are applied automatically to every matching order; with a code they only apply once the code is entered on the order.
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param promotion body models.PromotionRequest true "Promotion details"
+// @Success 201 {object} models.Promotion "Promotion created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 409 {object} models.ErrorResponse "Coupon code already in use"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /promotions [post]
+func CreatePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.PromotionRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if message := promotionRequestError(req); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": message})
+			return
+		}
+
+		active := true
+		if req.Active != nil {
+			active = *req.Active
+		}
+
+		promotion := models.Promotion{
+			ID:          primitive.NewObjectID(),
+			Name:        req.Name,
+			Description: req.Description,
+			Type:        req.Type,
+			Percent:     req.Percent,
+			Amount:      req.Amount,
+			BuyQuantity: req.BuyQuantity,
+			GetQuantity: req.GetQuantity,
+			FoodIDs:     req.FoodIDs,
+			MenuIDs:     req.MenuIDs,
+			Categories:  req.Categories,
+			Days:        req.Days,
+			StartTime:   req.StartTime,
+			EndTime:     req.EndTime,
+			StartsAt:    req.StartsAt,
+			EndsAt:      req.EndsAt,
+			Code:        normalizeCouponCode(req.Code),
+			UsageLimit:  req.UsageLimit,
+			Active:      active,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		result, err := getPromotionCollection().InsertOne(ctx, promotion)
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Coupon code already in use"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create promotion"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message":   "Promotion created successfully",
+			"id":        result.InsertedID,
+			"promotion": promotion,
+		})
+	}
+}
+
+// @Summary Update Promotion
+// @Description Change a promotion (requires promotions:manage). Its usage count is kept. Orders are discounted by the current rules until they are invoiced; existing invoices keep the discounts they were billed with.
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Param promotion body models.PromotionRequest true "Updated promotion details"
+// @Success 200 {object} models.SuccessResponse "Promotion updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Promotion not found"
+// @Failure 409 {object} models.ErrorResponse "Coupon code already in use"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /promotions/{id} [put]
+func UpdatePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		promotionID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(promotionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+			return
+		}
+
+		var req models.PromotionRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if message := promotionRequestError(req); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": message})
+			return
+		}
+
+		set := bson.M{
+			"name":         req.Name,
+			"description":  req.Description,
+			"type":         req.Type,
+			"percent":      req.Percent,
+			"amount":       req.Amount,
+			"buy_quantity": req.BuyQuantity,
+			"get_quantity": req.GetQuantity,
+			"food_ids":     req.FoodIDs,
+			"menu_ids":     req.MenuIDs,
+			"categories":   req.Categories,
+			"days":         req.Days,
+			"start_time":   req.StartTime,
+			"end_time":     req.EndTime,
+			"starts_at":    req.StartsAt,
+			"ends_at":      req.EndsAt,
+			"usage_limit":  req.UsageLimit,
+			"updated_at":   time.Now(),
+		}
+		if req.Active != nil {
+			set["active"] = *req.Active
+		}
+
+		// Promotions without a code have no code field, so that the unique
+		// index only covers coupon codes.
+		update := bson.M{"$set": set}
+		if code := normalizeCouponCode(req.Code); code != "" {
+			set["code"] = code
+		} else {
+			update["$unset"] = bson.M{"code": ""}
+		}
+
+		result, err := getPromotionCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Coupon code already in use"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update promotion"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Promotion not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Promotion updated successfully"})
+	}
+}
+
+// @Summary Delete Promotion
+// @Description Delete a promotion (requires promotions:manage). It stops applying to open orders; invoices keep the discounts they were billed with. Set active to false instead to keep its usage history.
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} models.SuccessResponse "Promotion deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Promotion not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /promotions/{id} [delete]
+func DeletePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		promotionID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(promotionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+			return
+		}
+
+		result, err := getPromotionCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete promotion"})
+			return
+		}
+
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Promotion not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Promotion deleted successfully"})
+	}
+}
+
+// @Summary Enter Coupon
+// @Description Enter a coupon code on an order. The coupon's promotion then discounts the order's matching items when it is totaled and invoiced. Codes are case-insensitive.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param coupon body models.CouponRequest true "Coupon code"
+// @Success 200 {object} models.OrderDetailResponse "Coupon applied"
+// @Failure 400 {object} models.ErrorResponse "Bad request, or coupon expired or not valid yet"
+// @Failure 404 {object} models.ErrorResponse "Order or coupon not found"
+// @Failure 409 {object} models.ErrorResponse "Order is closed or coupon has reached its usage limit"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /orders/{id}/coupons [post]
+func ApplyOrderCoupon() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+			return
+		}
+
+		var req models.CouponRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		code := normalizeCouponCode(req.Code)
+
+		var promotion models.Promotion
+		err = getPromotionCollection().FindOne(ctx, bson.M{"code": code, "active": true}).Decode(&promotion)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Coupon not found"})
+			return
+		}
+
+		now := time.Now()
+		if promotion.Expired(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Coupon has expired"})
+			return
+		}
+		if promotion.StartsAt != nil && now.Before(*promotion.StartsAt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Coupon is not valid yet"})
+			return
+		}
+		if promotion.Exhausted() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Coupon has reached its usage limit"})
+			return
+		}
+
+		updateOrderCoupons(ctx, c, objID, bson.M{"$addToSet": bson.M{"coupon_codes": code}}, "Coupon applied")
+	}
+}
+
+// @Summary Remove Coupon
+// @Description Remove a coupon code from an order
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param code path string true "Coupon code"
+// @Success 200 {object} models.OrderDetailResponse "Coupon removed"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Order not found"
+// @Failure 409 {object} models.ErrorResponse "Order is closed"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /orders/{id}/coupons/{code} [delete]
+func RemoveOrderCoupon() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+			return
+		}
+
+		code := normalizeCouponCode(c.Param("code"))
+		updateOrderCoupons(ctx, c, objID, bson.M{"$pull": bson.M{"coupon_codes": code}}, "Coupon removed")
+	}
+}
+
+// updateOrderCoupons applies update to the caller's order with the given ID
+// while it is open and responds with the order and its new totals.
+func updateOrderCoupons(ctx context.Context, c *gin.Context, objID primitive.ObjectID, update bson.M, message string) {
+	filter := ownerFilter(c, "orders:any")
+	filter["_id"] = objID
+
+	var order models.Order
+	if err := getOrderCollection().FindOne(ctx, filter).Decode(&order); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+
+	update["$set"] = bson.M{"updated_at": time.Now()}
+	result, err := getOrderCollection().UpdateOne(ctx,
+		bson.M{"_id": objID, "status": bson.M{"$in": openOrderStatuses}},
+		update,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order"})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Coupons cannot be changed on a " + order.Status + " order"})
+		return
+	}
+
+	detail, err := loadOrderDetail(ctx, bson.M{"_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"id":      objID,
+		"order":   detail,
+	})
+}
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"basic-backend/models"
+	"basic-backend/money"
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// promotionOrder is the order promotion types are applied in. Each promotion
+// discounts what earlier ones left of a line, so free items are taken out
+// before percentages, and fixed amounts come off last.
+var promotionOrder = map[string]int{
+	models.PromotionTypeBuyXGetY:   0,
+	models.PromotionTypePercentage: 1,
+	models.PromotionTypeFixed:      2,
+}
+
+// loadPromotions returns the active promotions that apply automatically or
+// whose code is in codes, oldest first.
+func loadPromotions(ctx context.Context, codes []string) ([]models.Promotion, error) {
+	if codes == nil {
+		codes = []string{}
+	}
+
+	filter := bson.M{
+		"active": true,
+		"$or": bson.A{
+			bson.M{"code": bson.M{"$exists": false}},
+			bson.M{"code": bson.M{"$in": codes}},
+		},
+	}
+
+	cursor, err := getPromotionCollection().Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	promotions := []models.Promotion{}
+	err = cursor.All(ctx, &promotions)
+	return promotions, err
+}
+
+// applyPromotions discounts the lines of detail with every promotion that
+// matches them and records the discount of each promotion in its totals.
+// Promotions that have reached their usage limit are skipped.
+func applyPromotions(detail *models.OrderDetail, promotions []models.Promotion) {
+	sort.SliceStable(promotions, func(i, j int) bool {
+		return promotionOrder[promotions[i].Type] < promotionOrder[promotions[j].Type]
+	})
+
+	discounts := []models.DiscountLine{}
+	total := money.New(0)
+	for _, promotion := range promotions {
+		if promotion.Exhausted() {
+			continue
+		}
+
+		var lines []*models.OrderLine
+		for i := range detail.Items {
+			if promotionMatches(promotion, detail.Items[i]) {
+				lines = append(lines, &detail.Items[i])
+			}
+		}
+
+		var amount money.Money
+		switch promotion.Type {
+		case models.PromotionTypePercentage:
+			amount = discountPercent(lines, promotion.Percent)
+		case models.PromotionTypeFixed:
+			amount = discountFixed(lines, promotion.Amount)
+		case models.PromotionTypeBuyXGetY:
+			amount = discountBuyXGetY(lines, promotion.BuyQuantity, promotion.GetQuantity)
+		}
+
+		if amount.IsPositive() {
+			discounts = append(discounts, models.DiscountLine{
+				PromotionID: promotion.ID.Hex(),
+				Name:        promotion.Name,
+				Code:        promotion.Code,
+				Amount:      amount,
+			})
+			total = total.Add(amount)
+		}
+	}
+
+	detail.Totals.Discount = total
+	detail.Totals.Discounts = discounts
+}
+
+// promotionMatches reports whether promotion covers line: the line's food,
+// menu or menu category is targeted, and the line was ordered while the
+// promotion was valid and inside its time window.
+func promotionMatches(promotion models.Promotion, line models.OrderLine) bool {
+	if len(promotion.FoodIDs)+len(promotion.MenuIDs)+len(promotion.Categories) > 0 &&
+		!containsString(promotion.FoodIDs, line.FoodID) &&
+		!containsString(promotion.MenuIDs, line.MenuID) &&
+		!containsCategory(promotion.Categories, line.Category) {
+		return false
+	}
+
+	orderedAt := line.CreatedAt.In(restaurantLocation)
+	if promotion.StartsAt != nil && orderedAt.Before(*promotion.StartsAt) {
+		return false
+	}
+	if promotion.Expired(orderedAt) {
+		return false
+	}
+
+	if len(promotion.Days) > 0 && !containsDay(promotion.Days, orderedAt.Weekday()) {
+		return false
+	}
+
+	if promotion.StartTime != "" && promotion.EndTime != "" {
+		start, end := minuteOfDay(promotion.StartTime), minuteOfDay(promotion.EndTime)
+		minute := orderedAt.Hour()*60 + orderedAt.Minute()
+		if start <= end {
+			return start <= minute && minute < end
+		}
+		// The window runs past midnight, such as 22:00 to 02:00.
+		return minute >= start || minute < end
+	}
+	return true
+}
+
+func containsCategory(categories []string, category string) bool {
+	for _, c := range categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDay(days []int, day time.Weekday) bool {
+	for _, d := range days {
+		if d == int(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// minuteOfDay converts a time of day in 15:04 format to minutes after
+// midnight.
+func minuteOfDay(value string) int {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+// remaining is the part of a line that earlier promotions have not taken off.
+func remaining(line *models.OrderLine) money.Money {
+	return line.LineTotal.Sub(line.Discount)
+}
+
+func minMoney(a, b money.Money) money.Money {
+	if a.Sub(b).IsPositive() {
+		return b
+	}
+	return a
+}
+
+// discountPercent takes percent off the remainder of each line.
+func discountPercent(lines []*models.OrderLine, percent float64) money.Money {
+	total := money.New(0)
+	for _, line := range lines {
+		discount := remaining(line).Percent(percent)
+		line.Discount = line.Discount.Add(discount)
+		total = total.Add(discount)
+	}
+	return total
+}
+
+// discountFixed takes amount off the lines together, at most what is left of
+// them, and spreads it over the lines in proportion to what is left of each.
+func discountFixed(lines []*models.OrderLine, amount money.Money) money.Money {
+	base := money.New(0)
+	for _, line := range lines {
+		base = base.Add(remaining(line))
+	}
+	if !base.IsPositive() {
+		return money.New(0)
+	}
+
+	amount = minMoney(amount, base)
+	allocated := money.New(0)
+	for i, line := range lines {
+		share := amount.Sub(allocated)
+		if i < len(lines)-1 {
+			share = amount.MulRat(big.NewRat(remaining(line).Amount, base.Amount))
+		}
+		share = minMoney(share, remaining(line))
+		line.Discount = line.Discount.Add(share)
+		allocated = allocated.Add(share)
+	}
+	return allocated
+}
+
+// discountBuyXGetY lines the units of the lines up from most to least
+// expensive and, in every group of buy + get units, makes the last get units
+// free. The free units of each line are counted from the positions its units
+// take in that line-up rather than by listing every unit, so large quantities
+// cost nothing extra.
+func discountBuyXGetY(lines []*models.OrderLine, buy int, get int) money.Money {
+	if buy < 1 || get < 1 {
+		return money.New(0)
+	}
+
+	sorted := append([]*models.OrderLine(nil), lines...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UnitPrice.Amount > sorted[j].UnitPrice.Amount
+	})
+
+	group := int64(buy + get)
+	// freeBefore counts the free units among the first n of the line-up.
+	freeBefore := func(n int64) int64 {
+		free := n / group * int64(get)
+		if rest := n%group - int64(buy); rest > 0 {
+			free += rest
+		}
+		return free
+	}
+
+	total := money.New(0)
+	position := int64(0)
+	for _, line := range sorted {
+		quantity := int64(line.Quantity)
+		free := freeBefore(position+quantity) - freeBefore(position)
+		position += quantity
+		if free <= 0 {
+			continue
+		}
+
+		discount := minMoney(line.UnitPrice.Mul(free), remaining(line))
+		line.Discount = line.Discount.Add(discount)
+		total = total.Add(discount)
+	}
+	return total
+}
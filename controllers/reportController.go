@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"basic-backend/models"
+	"basic-backend/money"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// reportPeriod reads the from and to query parameters as RFC 3339 times.
+// from defaults to the start of the current day in the restaurant's time zone
+// and to defaults to now.
+func reportPeriod(c *gin.Context) (time.Time, time.Time, error) {
+	now := time.Now().In(restaurantLocation)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, restaurantLocation)
+	to := now
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			return from, to, err
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			return from, to, err
+		}
+	}
+	return from, to, nil
+}
+
+// tipReportPipeline adds up the tips of the invoices paid in [from, to) by
+// the staff member they went to, most tipped first.
+func tipReportPipeline(from time.Time, to time.Time) []bson.M {
+	return []bson.M{
+		{"$match": bson.M{
+			"payment_status": models.PaymentStatusPaid,
+			"paid_at":        bson.M{"$gte": from, "$lt": to},
+			"tip":            bson.M{"$gt": money.New(0)},
+		}},
+		{"$group": bson.M{
+			"_id":      "$tip_staff_id",
+			"tips":     bson.M{"$sum": "$tip"},
+			"invoices": bson.M{"$sum": 1},
+		}},
+		{"$lookup": bson.M{
+			"from": "users",
+			"let": bson.M{"staff_id": bson.M{"$convert": bson.M{
+				"input": "$_id", "to": "objectId", "onError": nil, "onNull": nil,
+			}}},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$staff_id"}}}},
+				{"$project": bson.M{"name": bson.M{"$concat": bson.A{"$firstname", " ", "$lastname"}}}},
+			},
+			"as": "staff",
+		}},
+		{"$project": bson.M{
+			"_id":      0,
+			"staff_id": "$_id",
+			"name":     bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$staff.name", 0}}, ""}},
+			"tips":     1,
+			"invoices": 1,
+		}},
+		{"$sort": bson.M{"tips": -1}},
+	}
+}
+
+// @Summary Tip Report
+// @Description Add up the tips paid in a period by the staff member they went to, for distributing them at the end of a shift (requires tips:report). The period defaults to the current day in the restaurant's time zone up to now.
+// @Tags Report
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start of the period (RFC 3339)"
+// @Param to query string false "End of the period, exclusive (RFC 3339)"
+// @Success 200 {object} models.TipReport "Tips per staff member"
+// @Failure 400 {object} models.ErrorResponse "Invalid period"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /reports/tips [get]
+func GetTipReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		from, to, err := reportPeriod(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be RFC 3339 times"})
+			return
+		}
+
+		if !to.After(from) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+			return
+		}
+
+		cursor, err := getInvoiceCollection().Aggregate(ctx, tipReportPipeline(from, to))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching tips"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		staff := []models.StaffTips{}
+		if err = cursor.All(ctx, &staff); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding tips"})
+			return
+		}
+
+		total := money.New(0)
+		for _, s := range staff {
+			total = total.Add(s.Tips)
+		}
+
+		c.JSON(http.StatusOK, models.TipReport{From: from, To: to, Staff: staff, Total: total})
+	}
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getRoleCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "roles")
+}
+
+// SeedDefaultRoles creates any of models.DefaultRoles that do not exist yet and
+// applies models.RoleUpgrades to the built-in roles that have not had them.
+func SeedDefaultRoles() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, role := range models.DefaultRoles {
+		update := bson.M{
+			"$setOnInsert": bson.M{
+				"_id":                primitive.NewObjectID(),
+				"name":               role.Name,
+				"description":        role.Description,
+				"permissions":        role.Permissions,
+				"require_two_factor": role.RequireTwoFactor,
+				"built_in":           true,
+				"created_at":         time.Now(),
+				"updated_at":         time.Now(),
+			},
+		}
+
+		_, err := getRoleCollection().UpdateOne(ctx, bson.M{"name": role.Name}, update, options.Update().SetUpsert(true))
+		if err != nil {
+			log.Fatal("Failed to seed role "+role.Name+":", err)
+		}
+	}
+
+	for _, upgrade := range models.RoleUpgrades {
+		for name, permissions := range upgrade.Grants {
+			_, err := getRoleCollection().UpdateOne(ctx,
+				bson.M{"name": name, "built_in": true, "applied_upgrades": bson.M{"$ne": upgrade.ID}},
+				bson.M{"$addToSet": bson.M{
+					"permissions":      bson.M{"$each": permissions},
+					"applied_upgrades": upgrade.ID,
+				}},
+			)
+			if err != nil {
+				log.Fatal("Failed to upgrade role "+name+":", err)
+			}
+		}
+	}
+}
+
+// roleRequiresTwoFactor reports whether users with the named role must use
+// two-factor authentication.
+func roleRequiresTwoFactor(ctx context.Context, name string) (bool, error) {
+	var role models.Role
+	err := getRoleCollection().FindOne(ctx, bson.M{"name": name}).Decode(&role)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	return role.RequireTwoFactor, err
+}
+
+// roleExists reports whether a role with the given name has been defined.
+func roleExists(ctx context.Context, name string) (bool, error) {
+	count, err := getRoleCollection().CountDocuments(ctx, bson.M{"name": name})
+	return count > 0, err
+}
+
+// unknownPermissions returns the entries of permissions that are not in
+// models.Permissions.
+func unknownPermissions(permissions []string) []string {
+	known := map[string]bool{models.PermissionAll: true}
+	for _, p := range models.Permissions {
+		known[p] = true
+	}
+
+	var unknown []string
+	for _, p := range permissions {
+		if !known[p] {
+			unknown = append(unknown, p)
+		}
+	}
+	return unknown
+}
+
+// @Summary Get All Roles
+// @Description Retrieve every role with the permissions it grants
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Role "List of roles"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /roles [get]
+func GetRoles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var roles []models.Role
+		cursor, err := getRoleCollection().Find(ctx, bson.M{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching roles"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &roles); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding roles"})
+			return
+		}
+
+		c.JSON(http.StatusOK, roles)
+	}
+}
+
+// @Summary Get Permissions
+// @Description List every permission that can be granted to a role
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} string "List of permissions"
+// @Router /roles/permissions [get]
+func GetPermissions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, models.Permissions)
+	}
+}
+
+// @Summary Create Role
+// @Description Define a new staff role and the permissions it grants
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role body models.RoleRequest true "Role details"
+// @Success 201 {object} models.Role "Role created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 409 {object} models.ErrorResponse "Role already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /roles [post]
+func CreateRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.RoleRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if unknown := unknownPermissions(req.Permissions); len(unknown) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown permissions", "permissions": unknown})
+			return
+		}
+
+		exists, err := roleExists(ctx, req.Name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking role"})
+			return
+		}
+
+		if exists {
+			c.JSON(http.StatusConflict, gin.H{"error": "Role already exists"})
+			return
+		}
+
+		role := models.Role{
+			ID:               primitive.NewObjectID(),
+			Name:             req.Name,
+			Description:      req.Description,
+			Permissions:      req.Permissions,
+			RequireTwoFactor: req.RequireTwoFactor,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+		}
+
+		result, err := getRoleCollection().InsertOne(ctx, role)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create role"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Role created successfully",
+			"id":      result.InsertedID,
+			"role":    role,
+		})
+	}
+}
+
+// @Summary Update Role
+// @Description Change the description, permissions and two-factor requirement of a role. The role name cannot be changed.
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Param role body models.RoleRequest true "Updated role details"
+// @Success 200 {object} models.SuccessResponse "Role updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Role not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /roles/{id} [put]
+func UpdateRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(roleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+			return
+		}
+
+		var req models.RoleRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if unknown := unknownPermissions(req.Permissions); len(unknown) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown permissions", "permissions": unknown})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"description":        req.Description,
+				"permissions":        req.Permissions,
+				"require_two_factor": req.RequireTwoFactor,
+				"updated_at":         time.Now(),
+			},
+		}
+
+		result, err := getRoleCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+			return
+		}
+
+		helpers.InvalidateRoleCache()
+
+		c.JSON(http.StatusOK, gin.H{"message": "Role updated successfully"})
+	}
+}
+
+// @Summary Delete Role
+// @Description Delete a custom role. Built-in roles and roles still assigned to users cannot be deleted.
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} models.SuccessResponse "Role deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID or built-in role"
+// @Failure 404 {object} models.ErrorResponse "Role not found"
+// @Failure 409 {object} models.ErrorResponse "Role is still assigned to users"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /roles/{id} [delete]
+func DeleteRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(roleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+			return
+		}
+
+		var role models.Role
+		err = getRoleCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&role)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+			return
+		}
+
+		if role.BuiltIn {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Built-in roles cannot be deleted"})
+			return
+		}
+
+		count, err := getUserCollection().CountDocuments(ctx, bson.M{"usertype": role.Name})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking role assignments"})
+			return
+		}
+
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Role is still assigned to users", "users": count})
+			return
+		}
+
+		if _, err := getRoleCollection().DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete role"})
+			return
+		}
+
+		helpers.InvalidateRoleCache()
+
+		c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+	}
+}
@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getServiceChargeCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "servicecharges")
+}
+
+// loadServiceChargeRules returns the active service charge rules, oldest
+// first.
+func loadServiceChargeRules(ctx context.Context) ([]models.ServiceChargeRule, error) {
+	cursor, err := getServiceChargeCollection().Find(ctx, bson.M{"active": true}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rules := []models.ServiceChargeRule{}
+	err = cursor.All(ctx, &rules)
+	return rules, err
+}
+
+// serviceChargeRuleFor returns the matching rule with the highest MinGuests.
+// Of rules with the same MinGuests the oldest wins.
+func serviceChargeRuleFor(rules []models.ServiceChargeRule, partySize int, serviceType string) (models.ServiceChargeRule, bool) {
+	var best models.ServiceChargeRule
+	found := false
+	for _, rule := range rules {
+		if !rule.Matches(partySize, serviceType) {
+			continue
+		}
+		if !found || rule.MinGuests > best.MinGuests {
+			best = rule
+			found = true
+		}
+	}
+	return best, found
+}
+
+// orderPartySize returns the order's guest count or, if it has none, the
+// capacity of its table. Orders with neither have a party size of 0.
+func orderPartySize(ctx context.Context, order models.Order) (int, error) {
+	if order.GuestCount > 0 || order.TableID == "" {
+		return order.GuestCount, nil
+	}
+
+	objID, err := primitive.ObjectIDFromHex(order.TableID)
+	if err != nil {
+		return 0, nil
+	}
+
+	var table models.Table
+	err = getTableCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&table)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return table.Capacity, err
+}
+
+// @Summary Get Service Charge Rules
+// @Description Retrieve every service charge rule
+// @Tags ServiceCharge
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ServiceChargeRule "List of service charge rules"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /servicecharges [get]
+func GetServiceChargeRules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var rules []models.ServiceChargeRule
+		cursor, err := getServiceChargeCollection().Find(ctx, bson.M{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching service charge rules"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &rules); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding service charge rules"})
+			return
+		}
+
+		c.JSON(http.StatusOK, rules)
+	}
+}
+
+// @Summary Create Service Charge Rule
+// @Description Charge a service charge on orders for at least min_guests guests (requires servicecharges:manage). The party size is the order's guest_count or, without one, its table's capacity.
+// @Tags ServiceCharge
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule body models.ServiceChargeRuleRequest true "Service charge rule details"
+// @Success 201 {object} models.ServiceChargeRule "Service charge rule created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /servicecharges [post]
+func CreateServiceChargeRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.ServiceChargeRuleRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		active := true
+		if req.Active != nil {
+			active = *req.Active
+		}
+
+		rule := models.ServiceChargeRule{
+			ID:          primitive.NewObjectID(),
+			Name:        req.Name,
+			Rate:        req.Rate,
+			MinGuests:   req.MinGuests,
+			ServiceType: req.ServiceType,
+			Active:      active,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		result, err := getServiceChargeCollection().InsertOne(ctx, rule)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service charge rule"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Service charge rule created successfully",
+			"id":      result.InsertedID,
+			"rule":    rule,
+		})
+	}
+}
+
+// @Summary Update Service Charge Rule
+// @Description Change a service charge rule (requires servicecharges:manage). Orders are charged by the current rules until they are invoiced; existing invoices keep the service charge they were billed with.
+// @Tags ServiceCharge
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service charge rule ID"
+// @Param rule body models.ServiceChargeRuleRequest true "Updated service charge rule details"
+// @Success 200 {object} models.SuccessResponse "Service charge rule updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Service charge rule not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /servicecharges/{id} [put]
+func UpdateServiceChargeRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ruleID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(ruleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service charge rule ID"})
+			return
+		}
+
+		var req models.ServiceChargeRuleRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		set := bson.M{
+			"name":       req.Name,
+			"rate":       req.Rate,
+			"min_guests": req.MinGuests,
+			"updated_at": time.Now(),
+		}
+		if req.Active != nil {
+			set["active"] = *req.Active
+		}
+
+		update := bson.M{"$set": set}
+		if req.ServiceType != "" {
+			set["service_type"] = req.ServiceType
+		} else {
+			update["$unset"] = bson.M{"service_type": ""}
+		}
+
+		result, err := getServiceChargeCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service charge rule"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service charge rule not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Service charge rule updated successfully"})
+	}
+}
+
+// @Summary Delete Service Charge Rule
+// @Description Delete a service charge rule (requires servicecharges:manage)
+// @Tags ServiceCharge
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service charge rule ID"
+// @Success 200 {object} models.SuccessResponse "Service charge rule deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Service charge rule not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /servicecharges/{id} [delete]
+func DeleteServiceChargeRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ruleID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(ruleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service charge rule ID"})
+			return
+		}
+
+		result, err := getServiceChargeCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service charge rule"})
+			return
+		}
+
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service charge rule not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Service charge rule deleted successfully"})
+	}
+}
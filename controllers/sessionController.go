@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getSessionCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "sessions")
+}
+
+// createSession records a new session for the device making the request. Its
+// ID is the family ID the session's tokens were issued with.
+func createSession(ctx context.Context, c *gin.Context, userID primitive.ObjectID, familyID string, refreshToken string) error {
+	sessionID, err := primitive.ObjectIDFromHex(familyID)
+	if err != nil {
+		return err
+	}
+
+	claims, msg := helpers.ValidateToken(refreshToken)
+	if msg != "" {
+		return errors.New(msg)
+	}
+
+	now := time.Now()
+	session := models.Session{
+		ID:               sessionID,
+		UserID:           userID,
+		RefreshTokenHash: helpers.HashToken(refreshToken),
+		DeviceName:       c.GetHeader("X-Device-Name"),
+		UserAgent:        c.Request.UserAgent(),
+		IP:               c.ClientIP(),
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        claims.ExpiresAt.Time,
+	}
+
+	_, err = getSessionCollection().InsertOne(ctx, session)
+	return err
+}
+
+// revokeSession signs a single session out, including the access tokens that
+// were issued to it.
+func revokeSession(ctx context.Context, filter bson.M) (bool, error) {
+	var session models.Session
+	err := getSessionCollection().FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"revoked": true}},
+	).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := helpers.RevokeTokenFamily(ctx, session.ID.Hex()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// revokeUserSessions signs the user out everywhere by revoking all of their
+// access tokens and sessions.
+func revokeUserSessions(ctx context.Context, userID primitive.ObjectID) error {
+	if err := helpers.RevokeAllUserTokens(ctx, userID.Hex()); err != nil {
+		return err
+	}
+
+	_, err := getSessionCollection().UpdateMany(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	return err
+}
+
+// MigrateSessions removes the raw tokens older versions stored on user
+// documents. Their holders have to sign in again. It is safe to call on every
+// startup.
+func MigrateSessions() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := getUserCollection().UpdateMany(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"token": bson.M{"$exists": true}},
+			bson.M{"refreshtoken": bson.M{"$exists": true}},
+			bson.M{"refresh_token": bson.M{"$exists": true}},
+		}},
+		bson.M{"$unset": bson.M{"token": "", "refreshtoken": "", "refresh_token": ""}},
+	)
+	if err != nil {
+		log.Fatal("Failed to remove stored user tokens:", err)
+	}
+}
+
+// @Summary Get Sessions
+// @Description List the current user's active sessions with the device, user agent and address they were last used from. The session making the request is marked as current.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Session "Active sessions"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/sessions [get]
+func GetSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		userID, _ := primitive.ObjectIDFromHex(c.GetString("uid"))
+		filter := bson.M{
+			"user_id":    userID,
+			"revoked":    false,
+			"expires_at": bson.M{"$gt": time.Now()},
+		}
+
+		sessions := []models.Session{}
+		cursor, err := getSessionCollection().Find(ctx, filter, options.Find().SetSort(bson.M{"last_used_at": -1}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching sessions"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &sessions); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding sessions"})
+			return
+		}
+
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID.Hex() == c.GetString("family_id")
+		}
+
+		c.JSON(http.StatusOK, sessions)
+	}
+}
+
+// @Summary Delete Session
+// @Description Sign out one of the current user's sessions, revoking its refresh token and access tokens
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.SuccessResponse "Session revoked"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 404 {object} models.ErrorResponse "Session not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/sessions/{id} [delete]
+func DeleteSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(sessionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+			return
+		}
+
+		userID, _ := primitive.ObjectIDFromHex(c.GetString("uid"))
+		found, err := revokeSession(ctx, bson.M{"_id": objID, "user_id": userID, "revoked": false})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+			return
+		}
+
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+
+		if sessionID == c.GetString("family_id") {
+			helpers.ClearAuthCookie(c)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+	}
+}
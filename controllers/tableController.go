@@ -4,6 +4,7 @@ import (
 	"basic-backend/database"
 	"basic-backend/models"
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -187,7 +188,7 @@ func UpdateTable() gin.HandlerFunc {
 }
 
 // @Summary Delete Table
-// @Description Delete a table by ID
+// @Description Delete a table by ID. Orders at the table are handled according to ON_DELETE_TABLE_ORDERS; by default the delete is refused with 409 while they exist.
 // @Tags Table
 // @Accept json
 // @Produce json
@@ -196,6 +197,7 @@ func UpdateTable() gin.HandlerFunc {
 // @Success 200 {object} models.SuccessResponse "Table deleted successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid ID"
 // @Failure 404 {object} models.ErrorResponse "Table not found"
+// @Failure 409 {object} models.ErrorResponse "Still referenced by other records"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /tables/{id} [delete]
 func DeleteTable() gin.HandlerFunc {
@@ -210,13 +212,23 @@ func DeleteTable() gin.HandlerFunc {
 			return
 		}
 
-		result, err := getTableCollection().DeleteOne(ctx, bson.M{"_id": objID})
+		var deleted int64
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			var err error
+			deleted, err = deleteDocuments(sessCtx, "tables", bson.M{"_id": objID})
+			return err
+		})
+		var depErr *dependentsError
+		if errors.As(err, &depErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Table is still referenced", "dependents": depErr.Dependents})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete table"})
 			return
 		}
 
-		if result.DeletedCount == 0 {
+		if deleted == 0 {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
 			return
 		}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func getTaxClassCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "taxclasses")
+}
+
+// @Summary Get Tax Classes
+// @Description Retrieve every tax class with its dine-in and takeaway rates
+// @Tags TaxClass
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TaxClass "List of tax classes"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /taxclasses [get]
+func GetTaxClasses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var taxClasses []models.TaxClass
+		cursor, err := getTaxClassCollection().Find(ctx, bson.M{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching tax classes"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &taxClasses); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding tax classes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, taxClasses)
+	}
+}
+
+// @Summary Create Tax Class
+// @Description Define a tax class that foods can be assigned to (requires taxes:manage)
+// @Tags TaxClass
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taxclass body models.TaxClassRequest true "Tax class details"
+// @Success 201 {object} models.TaxClass "Tax class created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 409 {object} models.ErrorResponse "Tax class already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /taxclasses [post]
+func CreateTaxClass() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.TaxClassRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		taxClass := models.TaxClass{
+			ID:           primitive.NewObjectID(),
+			Name:         req.Name,
+			Description:  req.Description,
+			DineInRate:   req.DineInRate,
+			TakeawayRate: req.TakeawayRate,
+			Inclusive:    req.Inclusive,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+		result, err := getTaxClassCollection().InsertOne(ctx, taxClass)
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tax class already exists"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tax class"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message":   "Tax class created successfully",
+			"id":        result.InsertedID,
+			"tax_class": taxClass,
+		})
+	}
+}
+
+// @Summary Update Tax Class
+// @Description Change the name, rates or pricing of a tax class (requires taxes:manage). Orders are taxed at the current rates until they are invoiced; existing invoices keep the rates they were billed at.
+// @Tags TaxClass
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tax class ID"
+// @Param taxclass body models.TaxClassRequest true "Updated tax class details"
+// @Success 200 {object} models.SuccessResponse "Tax class updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Tax class not found"
+// @Failure 409 {object} models.ErrorResponse "Another tax class has this name"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /taxclasses/{id} [put]
+func UpdateTaxClass() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		taxClassID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(taxClassID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax class ID"})
+			return
+		}
+
+		var req models.TaxClassRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"name":          req.Name,
+				"description":   req.Description,
+				"dine_in_rate":  req.DineInRate,
+				"takeaway_rate": req.TakeawayRate,
+				"inclusive":     req.Inclusive,
+				"updated_at":    time.Now(),
+			},
+		}
+
+		result, err := getTaxClassCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Another tax class has this name"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tax class"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tax class not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Tax class updated successfully"})
+	}
+}
+
+// @Summary Delete Tax Class
+// @Description Delete a tax class (requires taxes:manage). Foods in the class are handled according to ON_DELETE_TAX_CLASS_FOODS; by default the delete is refused with 409 while they exist.
+// @Tags TaxClass
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tax class ID"
+// @Success 200 {object} models.SuccessResponse "Tax class deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Tax class not found"
+// @Failure 409 {object} models.ErrorResponse "Still referenced by other records"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /taxclasses/{id} [delete]
+func DeleteTaxClass() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		taxClassID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(taxClassID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax class ID"})
+			return
+		}
+
+		var deleted int64
+		err = database.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+			var err error
+			deleted, err = deleteDocuments(sessCtx, "taxclasses", bson.M{"_id": objID})
+			return err
+		})
+		var depErr *dependentsError
+		if errors.As(err, &depErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tax class is still referenced", "dependents": depErr.Dependents})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tax class"})
+			return
+		}
+
+		if deleted == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tax class not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Tax class deleted successfully"})
+	}
+}
@@ -0,0 +1,555 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const recoveryCodeCount = 10
+
+func totpIssuer() string {
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Restaurant API"
+	}
+	return issuer
+}
+
+// provisionTOTPSecret stores a new pending TOTP secret for the user. It only
+// replaces the active secret once confirmed by enableTOTP.
+func provisionTOTPSecret(ctx context.Context, user models.User) (gin.H, error) {
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"totp_pending_secret": secret,
+			"updatedat":           time.Now(),
+		},
+	}
+
+	if _, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": user.ID}, update); err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"secret":           secret,
+		"provisioning_uri": helpers.TOTPProvisioningURI(totpIssuer(), user.Email, secret),
+	}, nil
+}
+
+// enableTOTP confirms the user's pending secret with code, makes it the
+// active secret and returns a fresh set of recovery codes. ok is false when
+// there is no pending secret or the code does not match.
+func enableTOTP(ctx context.Context, user models.User, code string) (recoveryCodes []string, ok bool, err error) {
+	if user.TOTPPendingSecret == "" {
+		return nil, false, nil
+	}
+
+	step, valid := helpers.ValidateTOTP(user.TOTPPendingSecret, code, time.Now())
+	if !valid {
+		return nil, false, nil
+	}
+
+	recoveryCodes, err = helpers.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hashedCodes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		hashedCodes[i] = helpers.HashToken(recoveryCode)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"totp_enabled":   true,
+			"totp_secret":    user.TOTPPendingSecret,
+			"totp_last_step": step,
+			"recovery_codes": hashedCodes,
+			"updatedat":      time.Now(),
+		},
+		"$unset": bson.M{"totp_pending_secret": ""},
+	}
+
+	if _, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": user.ID}, update); err != nil {
+		return nil, false, err
+	}
+
+	return recoveryCodes, true, nil
+}
+
+// verifySecondFactor checks a TOTP code or an unused recovery code for a user
+// with two-factor authentication enabled. Accepted codes cannot be reused.
+func verifySecondFactor(ctx context.Context, user models.User, code string) (bool, error) {
+	if step, ok := helpers.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		result, err := getUserCollection().UpdateOne(ctx,
+			bson.M{"_id": user.ID, "$or": bson.A{
+				bson.M{"totp_last_step": bson.M{"$exists": false}},
+				bson.M{"totp_last_step": bson.M{"$lt": step}},
+			}},
+			bson.M{"$set": bson.M{"totp_last_step": step}},
+		)
+		if err != nil {
+			return false, err
+		}
+		return result.ModifiedCount > 0, nil
+	}
+
+	hashedCode := helpers.HashToken(code)
+	result, err := getUserCollection().UpdateOne(ctx,
+		bson.M{"_id": user.ID, "recovery_codes": hashedCode},
+		bson.M{"$pull": bson.M{"recovery_codes": hashedCode}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// challengeUser resolves the user a two-factor challenge token was issued to.
+// It writes the error response and returns false when the challenge cannot be
+// used.
+func challengeUser(ctx context.Context, c *gin.Context, challengeToken string) (models.User, bool) {
+	var user models.User
+
+	claims, errMsg := helpers.ValidateToken(challengeToken)
+	if errMsg != "" || claims.TokenType != helpers.ChallengeTokenType {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return user, false
+	}
+
+	objID, err := primitive.ObjectIDFromHex(claims.Uid)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return user, false
+	}
+
+	err = getUserCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return user, false
+	}
+
+	if user.Disabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+		return user, false
+	}
+
+	return user, true
+}
+
+// @Summary Start Two-Factor Enrollment During Login
+// @Description Provision a TOTP secret for an account whose role requires two-factor authentication but has not enrolled yet. Confirm it by completing /auth/login/2fa with a code from the authenticator app.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorEnrollRequest true "Challenge token from /auth/login"
+// @Success 200 {object} models.TwoFactorSetupResponse "TOTP secret and provisioning URI"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or already enrolled"
+// @Failure 401 {object} models.ErrorResponse "Invalid or expired challenge token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/login/2fa/enroll [post]
+func EnrollTwoFactorLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.TwoFactorEnrollRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		user, ok := challengeUser(ctx, c, req.ChallengeToken)
+		if !ok {
+			return
+		}
+
+		if user.TOTPEnabled {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is already enabled"})
+			return
+		}
+
+		setup, err := provisionTOTPSecret(ctx, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error provisioning two-factor authentication"})
+			return
+		}
+
+		c.JSON(http.StatusOK, setup)
+	}
+}
+
+// @Summary Complete Two-Factor Login
+// @Description Exchange a challenge token from /auth/login and a TOTP or recovery code for access and refresh tokens. For accounts enrolling during login, a valid code also enables two-factor authentication and the response includes recovery codes.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} models.LoginResponse "Login successful"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 401 {object} models.ErrorResponse "Invalid challenge token or code"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled"
+// @Failure 429 {object} models.ErrorResponse "Too many failed attempts; see Retry-After"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/login/2fa [post]
+func LoginTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.TwoFactorLoginRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		user, ok := challengeUser(ctx, c, req.ChallengeToken)
+		if !ok {
+			return
+		}
+
+		// Wrong codes count towards the same lockout as wrong passwords.
+		accountKey := accountThrottleKey(user.Email)
+		ipKey := ipThrottleKey(c.ClientIP())
+		lockedFor, err := loginLockedFor(ctx, accountKey, ipKey)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking login attempts"})
+			return
+		}
+
+		if lockedFor > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(lockedFor.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed login attempts, try again later"})
+			return
+		}
+
+		var recoveryCodes []string
+		if user.TOTPEnabled {
+			ok, err = verifySecondFactor(ctx, user, req.Code)
+		} else {
+			recoveryCodes, ok, err = enableTOTP(ctx, user, req.Code)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error verifying code"})
+			return
+		}
+
+		if !ok {
+			if err := recordLoginFailure(ctx, accountKey, accountThrottle); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+				return
+			}
+
+			if err := recordLoginFailure(ctx, ipKey, ipThrottle); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+				return
+			}
+
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+			return
+		}
+
+		if err := clearLoginFailures(ctx, accountKey); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+			return
+		}
+
+		token, refreshToken, err := issueLoginTokens(ctx, c, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating tokens"})
+			return
+		}
+
+		response := loginResponse(user, token, refreshToken)
+		if recoveryCodes != nil {
+			response["recovery_codes"] = recoveryCodes
+		}
+		helpers.SetAuthCookie(c, token)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary Set Up Two-Factor Authentication
+// @Description Provision a new TOTP secret for the current user. Two-factor authentication is enabled once the secret is confirmed at /auth/2fa/enable.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TwoFactorSetupResponse "TOTP secret and provisioning URI"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/2fa/setup [post]
+func SetupTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": c.GetString("email")}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		setup, err := provisionTOTPSecret(ctx, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error provisioning two-factor authentication"})
+			return
+		}
+
+		c.JSON(http.StatusOK, setup)
+	}
+}
+
+// @Summary Enable Two-Factor Authentication
+// @Description Confirm the secret from /auth/2fa/setup with a code from the authenticator app. Returns single-use recovery codes, which are only shown once.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} models.TwoFactorEnableResponse "Two-factor authentication enabled"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or code"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/2fa/enable [post]
+func EnableTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.TwoFactorCodeRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": c.GetString("email")}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		recoveryCodes, ok, err := enableTOTP(ctx, user, req.Code)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error enabling two-factor authentication"})
+			return
+		}
+
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code or no pending two-factor setup"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":        "Two-factor authentication enabled",
+			"recovery_codes": recoveryCodes,
+		})
+	}
+}
+
+// @Summary Disable Two-Factor Authentication
+// @Description Turn off two-factor authentication for the current user, confirmed with a TOTP or recovery code. Not allowed when the user's role requires two-factor authentication. Wrong codes count towards the login lockout.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} models.SuccessResponse "Two-factor authentication disabled"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body, invalid code or not enabled"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid authentication token"
+// @Failure 403 {object} models.ErrorResponse "Role requires two-factor authentication"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 429 {object} models.ErrorResponse "Too many failed attempts; see Retry-After"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/2fa/disable [post]
+func DisableTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.TwoFactorCodeRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": c.GetString("email")}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if !user.TOTPEnabled {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+			return
+		}
+
+		requireTwoFactor, err := roleRequiresTwoFactor(ctx, user.UserType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading role"})
+			return
+		}
+
+		if requireTwoFactor {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role requires two-factor authentication"})
+			return
+		}
+
+		// Wrong codes count towards the same lockout as wrong passwords.
+		accountKey := accountThrottleKey(user.Email)
+		ipKey := ipThrottleKey(c.ClientIP())
+		lockedFor, err := loginLockedFor(ctx, accountKey, ipKey)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking login attempts"})
+			return
+		}
+
+		if lockedFor > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(lockedFor.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed login attempts, try again later"})
+			return
+		}
+
+		ok, err := verifySecondFactor(ctx, user, req.Code)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error verifying code"})
+			return
+		}
+
+		if !ok {
+			if err := recordLoginFailure(ctx, accountKey, accountThrottle); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+				return
+			}
+
+			if err := recordLoginFailure(ctx, ipKey, ipThrottle); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+				return
+			}
+
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+			return
+		}
+
+		if err := clearLoginFailures(ctx, accountKey); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error recording login attempt"})
+			return
+		}
+
+		if err := resetTwoFactor(ctx, user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error disabling two-factor authentication"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+	}
+}
+
+func resetTwoFactor(ctx context.Context, userID primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"totp_enabled": false,
+			"updatedat":    time.Now(),
+		},
+		"$unset": bson.M{
+			"totp_secret":         "",
+			"totp_pending_secret": "",
+			"totp_last_step":      "",
+			"recovery_codes":      "",
+		},
+	}
+
+	_, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": userID}, update)
+	return err
+}
+
+// @Summary Reset User Two-Factor Authentication
+// @Description Remove a user's TOTP secret and recovery codes, e.g. after a lost device. If their role requires two-factor authentication they enroll again at next login (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.SuccessResponse "Two-factor authentication reset"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/2fa/reset [post]
+func ResetUserTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		count, err := getUserCollection().CountDocuments(ctx, bson.M{"_id": objID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+			return
+		}
+
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if err := resetTwoFactor(ctx, objID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error resetting two-factor authentication"})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, objID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset"})
+	}
+}
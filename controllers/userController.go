@@ -0,0 +1,433 @@
+package controllers
+
+import (
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BootstrapAdmin provisions the first admin account from the
+// BOOTSTRAP_ADMIN_EMAIL and BOOTSTRAP_ADMIN_PASSWORD environment variables.
+// It does nothing once an admin exists. Existing accounts are never promoted,
+// since anyone may have signed up with that email, so the server refuses to
+// start when the email is already taken.
+func BootstrapAdmin() {
+	email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL")
+	password := os.Getenv("BOOTSTRAP_ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := getUserCollection().CountDocuments(ctx, bson.M{"usertype": "ADMIN"})
+	if err != nil {
+		log.Fatal("Failed to check for existing admin:", err)
+	}
+
+	if count > 0 {
+		return
+	}
+
+	count, err = getUserCollection().CountDocuments(ctx, bson.M{"email": email})
+	if err != nil {
+		log.Fatal("Failed to check bootstrap admin email:", err)
+	}
+
+	if count > 0 {
+		log.Fatal("BOOTSTRAP_ADMIN_EMAIL ", email, " already belongs to a non-admin account; choose another email")
+	}
+
+	hashedPassword, err := helpers.HashPassword(password)
+	if err != nil {
+		log.Fatal("Failed to hash bootstrap admin password:", err)
+	}
+
+	admin := models.User{
+		ID:            primitive.NewObjectID(),
+		FirstName:     "Admin",
+		LastName:      "Account",
+		Email:         email,
+		Password:      hashedPassword,
+		UserType:      "ADMIN",
+		EmailVerified: true,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if _, err := getUserCollection().InsertOne(ctx, admin); err != nil {
+		log.Fatal("Failed to create bootstrap admin:", err)
+	}
+
+	log.Println("Created bootstrap admin", email)
+}
+
+// @Summary Get All Users
+// @Description Retrieve all user accounts, optionally filtered by role (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_type query string false "Filter by role"
+// @Success 200 {array} models.User "List of users (password fields will be empty)"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users [get]
+func GetUsers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userType := c.Query("user_type")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if userType != "" {
+			filter["usertype"] = userType
+		}
+
+		var users []models.User
+		cursor, err := getUserCollection().Find(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching users"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &users); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding users"})
+			return
+		}
+
+		for i := range users {
+			users[i].Password = ""
+		}
+
+		c.JSON(http.StatusOK, users)
+	}
+}
+
+// @Summary Create User
+// @Description Create a user account with any role, e.g. to provision staff. Set email_verified to skip email verification (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body models.UserCreateRequest true "User details"
+// @Success 201 {object} models.User "User created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request or unknown role"
+// @Failure 409 {object} models.ErrorResponse "Email already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users [post]
+func CreateUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.UserCreateRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		exists, err := roleExists(ctx, req.UserType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking role"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role " + req.UserType})
+			return
+		}
+
+		count, err := getUserCollection().CountDocuments(ctx, bson.M{"email": req.Email})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking email"})
+			return
+		}
+
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+			return
+		}
+
+		hashedPassword, err := helpers.HashPassword(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error hashing password"})
+			return
+		}
+
+		user := models.User{
+			ID:            primitive.NewObjectID(),
+			FirstName:     req.FirstName,
+			LastName:      req.LastName,
+			Email:         req.Email,
+			Password:      hashedPassword,
+			Phone:         req.Phone,
+			UserType:      req.UserType,
+			EmailVerified: req.EmailVerified,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+
+		if _, err := getUserCollection().InsertOne(ctx, user); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "User was not created"})
+			return
+		}
+
+		if !user.EmailVerified {
+			if err := sendVerificationEmail(ctx, user); err != nil {
+				log.Println("Failed to send verification email:", err)
+			}
+		}
+
+		user.Password = ""
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "User created successfully",
+			"id":      user.ID,
+			"user":    user,
+		})
+	}
+}
+
+// @Summary Update User Role
+// @Description Change a user's role. The user's existing sessions are revoked so the new role takes effect immediately (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param role body models.UserRoleUpdateRequest true "New role"
+// @Success 200 {object} models.SuccessResponse "User role updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/role [put]
+func UpdateUserRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		var req models.UserRoleUpdateRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if userID == c.GetString("uid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change your own role"})
+			return
+		}
+
+		exists, err := roleExists(ctx, req.UserType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking role"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role " + req.UserType})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"usertype":  req.UserType,
+				"updatedat": time.Now(),
+			},
+		}
+
+		result, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user role"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, objID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "User role updated successfully"})
+	}
+}
+
+// @Summary Update User Status
+// @Description Disable or re-enable a user account. Disabling revokes all of the user's sessions (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param status body models.UserStatusUpdateRequest true "New status"
+// @Success 200 {object} models.SuccessResponse "User status updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/status [put]
+func UpdateUserStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		var req models.UserStatusUpdateRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.Disabled && userID == c.GetString("uid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot disable your own account"})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"disabled":  req.Disabled,
+				"updatedat": time.Now(),
+			},
+		}
+
+		result, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if req.Disabled {
+			if err := revokeUserSessions(ctx, objID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "User status updated successfully"})
+	}
+}
+
+// @Summary Revoke User Sessions
+// @Description Sign a user out of every device by revoking all of their access and refresh tokens (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.SuccessResponse "Sessions revoked successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/revoke-sessions [post]
+func RevokeUserSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		count, err := getUserCollection().CountDocuments(ctx, bson.M{"_id": objID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+			return
+		}
+
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, objID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Sessions revoked successfully"})
+	}
+}
+
+// @Summary Unlock User
+// @Description Clear the failed login attempts recorded for a user so a locked out account can log in again (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.SuccessResponse "User unlocked successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/unlock [post]
+func UnlockUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		var user models.User
+		err = getUserCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		if err := clearLoginFailures(ctx, accountThrottleKey(user.Email)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock user"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+	}
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"basic-backend/database"
+	"basic-backend/helpers"
+	"basic-backend/models"
+	"context"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func getUserTokenCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "usertokens")
+}
+
+// issueUserToken creates a single-use token for purpose, replacing any unused
+// token the user already has for the same purpose. The raw token is returned
+// and only its hash is stored.
+func issueUserToken(ctx context.Context, userID primitive.ObjectID, purpose string, ttl time.Duration) (string, error) {
+	token, err := helpers.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = getUserTokenCollection().DeleteMany(ctx, bson.M{"user_id": userID, "purpose": purpose, "used_at": nil})
+	if err != nil {
+		return "", err
+	}
+
+	record := models.UserToken{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+
+	if _, err := getUserTokenCollection().InsertOne(ctx, record); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeUserToken marks an unexpired, unused token for purpose as used and
+// returns it. mongo.ErrNoDocuments is returned when no such token exists.
+func consumeUserToken(ctx context.Context, token string, purpose string) (models.UserToken, error) {
+	now := time.Now()
+	filter := bson.M{
+		"token_hash": helpers.HashToken(token),
+		"purpose":    purpose,
+		"used_at":    nil,
+		"expires_at": bson.M{"$gt": now},
+	}
+
+	var record models.UserToken
+	err := getUserTokenCollection().FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"used_at": now}}).Decode(&record)
+	return record, err
+}
+
+// appBaseURL is the public URL used to build links in emails.
+func appBaseURL() string {
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return baseURL
+}
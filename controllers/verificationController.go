@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"basic-backend/mailer"
+	"basic-backend/models"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const emailVerificationTTL = 24 * time.Hour
+
+// emailVerificationRequired reports whether users must verify their email
+// address before they can log in. It is on unless
+// REQUIRE_EMAIL_VERIFICATION is set to "false".
+func emailVerificationRequired() bool {
+	return os.Getenv("REQUIRE_EMAIL_VERIFICATION") != "false"
+}
+
+// sendVerificationEmail emails the user a single-use link to /auth/verify.
+func sendVerificationEmail(ctx context.Context, user models.User) error {
+	token, err := issueUserToken(ctx, user.ID, models.UserTokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := appBaseURL() + "/auth/verify?token=" + url.QueryEscape(token)
+	return mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: "Hi " + user.FirstName + ",\n\n" +
+			"Please confirm your email address by opening the link below. It expires in 24 hours.\n\n" +
+			link + "\n",
+	})
+}
+
+// BackfillEmailVerified marks accounts created before email verification was
+// introduced as verified so that they can still log in.
+func BackfillEmailVerified() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := getUserCollection().UpdateMany(ctx,
+		bson.M{"email_verified": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"email_verified": true}},
+	)
+	if err != nil {
+		log.Fatal("Failed to backfill email verification:", err)
+	}
+}
+
+// @Summary Verify Email
+// @Description Confirm an email address using the token from a verification email
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} models.SuccessResponse "Email verified successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid or expired token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/verify [get]
+func VerifyEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if token == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Verification token is required"})
+			return
+		}
+
+		record, err := consumeUserToken(ctx, token, models.UserTokenEmailVerification)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking verification token"})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"email_verified": true,
+				"updatedat":      time.Now(),
+			},
+		}
+
+		result, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": record.UserID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+	}
+}
+
+// @Summary Resend Verification Email
+// @Description Send a new verification link to an unverified account. The response is the same whether or not the email is registered.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ResendVerificationRequest true "Account email"
+// @Success 200 {object} models.SuccessResponse "Verification email sent if the account needs one"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/verify/resend [post]
+func ResendVerification() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var req models.ResendVerificationRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := validate.Struct(req)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		response := gin.H{"message": "If the account needs verification, a new link has been sent"}
+
+		var user models.User
+		err := getUserCollection().FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
+		if err == mongo.ErrNoDocuments || (err == nil && (user.EmailVerified || user.Disabled)) {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+			return
+		}
+
+		if err := sendVerificationEmail(ctx, user); err != nil {
+			log.Println("Failed to send verification email:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sending verification email"})
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary Verify User
+// @Description Mark a user's email address as verified without a verification email, e.g. for internal staff accounts (requires users:manage)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.SuccessResponse "User verified successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/verify [post]
+func VerifyUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("id")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"email_verified": true,
+				"updatedat":      time.Now(),
+			},
+		}
+
+		result, err := getUserCollection().UpdateOne(ctx, bson.M{"_id": objID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify user"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "User verified successfully"})
+	}
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"basic-backend/money"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MigrateFieldNames renames fields that older versions stored under the
+// driver's default lowercased names to the snake_case names the models now
+// declare. Where both exist the snake_case field was written by a later update
+// and wins. It is safe to call on every startup.
+func MigrateFieldNames() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	renames := map[string]map[string]string{
+		"orders": {
+			"tableid":   "table_id",
+			"orderdate": "order_date",
+			"createdat": "created_at",
+			"updatedat": "updated_at",
+		},
+		"foods": {
+			"foodimage": "food_image",
+			"menuid":    "menu_id",
+			"createdat": "created_at",
+			"updatedat": "updated_at",
+		},
+		"invoices": {
+			"orderid":       "order_id",
+			"paymentmethod": "payment_method",
+			"totalamount":   "total_amount",
+			"paymentstatus": "payment_status",
+			"createdat":     "created_at",
+			"updatedat":     "updated_at",
+		},
+		"orderitems": {
+			"orderid":   "order_id",
+			"foodid":    "food_id",
+			"unitprice": "unit_price",
+			"createdat": "created_at",
+			"updatedat": "updated_at",
+		},
+	}
+
+	for collectionName, fields := range renames {
+		collection := GetCollection(Client, collectionName)
+		for oldName, newName := range fields {
+			_, err := collection.UpdateMany(ctx,
+				bson.M{oldName: bson.M{"$exists": true}, newName: bson.M{"$exists": true}},
+				bson.M{"$unset": bson.M{oldName: ""}},
+			)
+			if err != nil {
+				log.Fatal("Failed to migrate "+collectionName+"."+oldName+":", err)
+			}
+
+			_, err = collection.UpdateMany(ctx,
+				bson.M{oldName: bson.M{"$exists": true}},
+				bson.M{"$rename": bson.M{oldName: newName}},
+			)
+			if err != nil {
+				log.Fatal("Failed to migrate "+collectionName+"."+oldName+":", err)
+			}
+		}
+	}
+
+	fmt.Println("✅ Field names migrated!")
+}
+
+// MigrateMoneyFields converts amounts that older versions stored as doubles or
+// integers to Decimal128, rounded to the currency's minor units. It is safe to
+// call on every startup.
+func MigrateMoneyFields() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	numeric := bson.M{"$type": bson.A{"double", "int", "long"}}
+	toDecimal := func(expr string) bson.M {
+		return bson.M{"$round": bson.A{bson.M{"$toDecimal": expr}, money.Digits()}}
+	}
+
+	fields := map[string][]string{
+		"foods":      {"price"},
+		"orderitems": {"unit_price"},
+		"invoices":   {"subtotal", "service_charge", "tax", "discount", "total_amount"},
+	}
+
+	for collectionName, names := range fields {
+		collection := GetCollection(Client, collectionName)
+		for _, name := range names {
+			_, err := collection.UpdateMany(ctx,
+				bson.M{name: numeric},
+				[]bson.M{{"$set": bson.M{name: toDecimal("$" + name)}}},
+			)
+			if err != nil {
+				log.Fatal("Failed to migrate "+collectionName+"."+name+":", err)
+			}
+		}
+	}
+
+	// Amounts inside the line and tax arrays of invoices.
+	arrays := map[string][]string{
+		"lines": {"unit_price", "line_total", "tax"},
+		"taxes": {"taxable", "tax"},
+	}
+
+	invoices := GetCollection(Client, "invoices")
+	for array, names := range arrays {
+		filter := bson.A{}
+		converted := bson.M{}
+		for _, name := range names {
+			filter = append(filter, bson.M{array + "." + name: numeric})
+			converted[name] = toDecimal("$$entry." + name)
+		}
+
+		_, err := invoices.UpdateMany(ctx,
+			bson.M{"$or": filter},
+			[]bson.M{{"$set": bson.M{array: bson.M{"$map": bson.M{
+				"input": "$" + array,
+				"as":    "entry",
+				"in":    bson.M{"$mergeObjects": bson.A{"$$entry", converted}},
+			}}}}},
+		)
+		if err != nil {
+			log.Fatal("Failed to migrate invoices."+array+":", err)
+		}
+	}
+
+	fmt.Println("✅ Amounts migrated to Decimal128!")
+}
@@ -23,47 +23,52 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate user with email and password, returns JWT access token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys that access tokens can be verified with, identified by the kid token header. Empty when tokens are signed with an HS256 secret.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Authentication"
                 ],
-                "summary": "User Login",
-                "parameters": [
-                    {
-                        "description": "Email and Password",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "JSON Web Key Set",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
+                            "$ref": "#/definitions/models.JWKSResponse"
                         }
                     }
+                }
+            }
+        },
+        "/apikeys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List device API keys, newest first. Key values are never returned after creation (requires apikeys:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key"
                 ],
+                "summary": "Get API Keys",
                 "responses": {
                     "200": {
-                        "description": "Login successful with authentication token and user details",
+                        "description": "List of API keys",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid email or password",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIKey"
+                            }
                         }
                     },
                     "500": {
@@ -73,11 +78,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/signup": {
+            },
             "post": {
-                "description": "Register a new user account with email, password, and profile information",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue an API key for a device such as a POS terminal or kitchen display. The key is only shown in this response. It grants exactly the listed permissions, which cannot include * or any :manage permission (requires apikeys:manage)",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,35 +93,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "API Key"
                 ],
-                "summary": "User Signup",
+                "summary": "Create API Key",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
-                        "name": "user",
+                        "description": "API key details",
+                        "name": "apikey",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SignupRequest"
+                            "$ref": "#/definitions/models.APIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User created successfully with authentication token",
+                        "description": "API key created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.SignupResponse"
+                            "$ref": "#/definitions/models.APIKeyCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Email already exists in the system",
+                        "description": "Bad request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -127,14 +129,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/user": {
-            "get": {
+        "/apikeys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get authenticated user profile details (requires valid JWT token)",
+                "description": "Revoke a device API key. Requests using it are rejected immediately (requires apikeys:manage)",
                 "consumes": [
                     "application/json"
                 ],
@@ -142,24 +144,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "API Key"
+                ],
+                "summary": "Revoke API Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Current User",
                 "responses": {
                     "200": {
-                        "description": "User profile details (password field will be empty)",
+                        "description": "API key revoked",
                         "schema": {
-                            "$ref": "#/definitions/models.UserSummary"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
-                    "401": {
-                        "description": "Missing or invalid authentication token",
+                    "400": {
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User not found in database",
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -167,14 +184,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/foods": {
-            "get": {
+        "/auth/2fa/disable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a complete list of all available food items in the restaurant",
+                "description": "Turn off two-factor authentication for the current user, confirmed with a TOTP or recovery code. Not allowed when the user's role requires two-factor authentication. Wrong codes count towards the login lockout.",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,17 +199,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Food"
+                    "Authentication"
+                ],
+                "summary": "Disable Two-Factor Authentication",
+                "parameters": [
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
+                        }
+                    }
                 ],
-                "summary": "Get All Foods",
                 "responses": {
                     "200": {
-                        "description": "Array of all food items with details",
+                        "description": "Two-factor authentication disabled",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FoodResponse"
-                            }
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, invalid code or not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
@@ -201,21 +232,41 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Role requires two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Database error while fetching foods",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/2fa/enable": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new food item in the restaurant menu (Admin only)",
+                "description": "Confirm the secret from /auth/2fa/setup with a code from the authenticator app. Returns single-use recovery codes, which are only shown once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -223,29 +274,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Food"
+                    "Authentication"
                 ],
-                "summary": "Create Food",
+                "summary": "Enable Two-Factor Authentication",
                 "parameters": [
                     {
-                        "description": "Food item details (name, price, image, menu_id)",
-                        "name": "food",
+                        "description": "Code from the authenticator app",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FoodCreateRequest"
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Food created successfully with generated ID",
+                    "200": {
+                        "description": "Two-factor authentication enabled",
                         "schema": {
-                            "$ref": "#/definitions/models.FoodResponse"
+                            "$ref": "#/definitions/models.TwoFactorEnableResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation failed",
+                        "description": "Invalid request body or code",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -256,8 +307,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Database error while creating food",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -265,14 +322,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/foods/{id}": {
-            "get": {
+        "/auth/2fa/setup": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve detailed information about a specific food item using its unique ID",
+                "description": "Provision a new TOTP secret for the current user. Two-factor authentication is enabled once the secret is confirmed at /auth/2fa/enable.",
                 "consumes": [
                     "application/json"
                 ],
@@ -280,53 +337,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Food"
-                ],
-                "summary": "Get Food by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"507f1f77bcf86cd799439011\"",
-                        "description": "Food MongoDB ObjectID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Authentication"
                 ],
+                "summary": "Set Up Two-Factor Authentication",
                 "responses": {
                     "200": {
-                        "description": "Food item details",
+                        "description": "TOTP secret and provisioning URI",
                         "schema": {
-                            "$ref": "#/definitions/models.FoodResponse"
+                            "$ref": "#/definitions/models.TwoFactorSetupResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid MongoDB ObjectID format",
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Missing or invalid authentication token",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Food item not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update an existing food item's information (Admin only)",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with email and password, returns JWT access token. When the account has two-factor authentication enabled, or its role requires it, a challenge token is returned instead that must be completed at /auth/login/2fa.",
                 "consumes": [
                     "application/json"
                 ],
@@ -334,68 +378,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Food"
+                    "Authentication"
                 ],
-                "summary": "Update Food",
+                "summary": "User Login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"507f1f77bcf86cd799439011\"",
-                        "description": "Food MongoDB ObjectID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated food details",
-                        "name": "food",
+                        "description": "Email and Password",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FoodCreateRequest"
+                            "$ref": "#/definitions/models.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Food updated successfully",
+                        "description": "Login successful with authentication token and user details, or a two-factor challenge",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.LoginResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format or request body",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Missing or invalid authentication token",
+                        "description": "Invalid email or password",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Food item not found",
+                    "403": {
+                        "description": "Account is disabled or email not verified",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts for this account or address; see Retry-After",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error while updating food",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Permanently delete a food item from the menu (Admin only)",
+            }
+        },
+        "/auth/login/2fa": {
+            "post": {
+                "description": "Exchange a challenge token from /auth/login and a TOTP or recovery code for access and refresh tokens. For accounts enrolling during login, a valid code also enables two-factor authentication and the response includes recovery codes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -403,80 +442,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Food"
+                    "Authentication"
                 ],
-                "summary": "Delete Food",
+                "summary": "Complete Two-Factor Login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"507f1f77bcf86cd799439011\"",
-                        "description": "Food MongoDB ObjectID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                        "description": "Challenge token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorLoginRequest"
+                        }
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Food deleted successfully",
+                        "description": "Login successful",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.LoginResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid MongoDB ObjectID format",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Missing or invalid authentication token",
+                        "description": "Invalid challenge token or code",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Food item not found",
+                    "403": {
+                        "description": "Account is disabled",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Database error while deleting food",
+                    "429": {
+                        "description": "Too many failed attempts; see Retry-After",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/invoices": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieve a list of all invoices",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Invoice"
-                ],
-                "summary": "Get All Invoices",
-                "responses": {
-                    "200": {
-                        "description": "List of invoices",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.InvoiceResponse"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -485,14 +494,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/login/2fa/enroll": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new invoice",
+                "description": "Provision a TOTP secret for an account whose role requires two-factor authentication but has not enrolled yet. Confirm it by completing /auth/login/2fa with a code from the authenticator app.",
                 "consumes": [
                     "application/json"
                 ],
@@ -500,29 +506,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Authentication"
                 ],
-                "summary": "Create Invoice",
+                "summary": "Start Two-Factor Enrollment During Login",
                 "parameters": [
                     {
-                        "description": "Invoice details",
-                        "name": "invoice",
+                        "description": "Challenge token from /auth/login",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.InvoiceCreateRequest"
+                            "$ref": "#/definitions/models.TwoFactorEnrollRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Invoice created successfully",
+                    "200": {
+                        "description": "TOTP secret and provisioning URI",
                         "schema": {
-                            "$ref": "#/definitions/models.InvoiceResponse"
+                            "$ref": "#/definitions/models.TwoFactorSetupResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid request body or already enrolled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired challenge token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -536,14 +548,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/invoices/{id}": {
-            "get": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific invoice by its ID",
+                "description": "Revoke the access token used for this request and end its session, and clear the auth cookie if enabled",
                 "consumes": [
                     "application/json"
                 ],
@@ -551,46 +563,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
-                ],
-                "summary": "Get Invoice by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Authentication"
                 ],
+                "summary": "Logout",
                 "responses": {
                     "200": {
-                        "description": "Invoice details",
+                        "description": "Logged out successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.InvoiceResponse"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid ID",
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Invoice not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update an existing invoice",
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Email a single-use password reset link to the account. The response is the same whether or not the email is registered.",
                 "consumes": [
                     "application/json"
                 ],
@@ -598,42 +598,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Authentication"
                 ],
-                "summary": "Update Invoice",
+                "summary": "Forgot Password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated invoice details",
-                        "name": "invoice",
+                        "description": "Account email",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.InvoiceCreateRequest"
+                            "$ref": "#/definitions/models.ForgotPasswordRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Invoice updated successfully",
+                        "description": "Reset instructions sent if the account exists",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Invoice not found",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -647,14 +634,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/menus": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieve a list of all menus",
+        "/auth/password/reset": {
+            "post": {
+                "description": "Set a new password using a token from a password reset email. All existing sessions are revoked.",
                 "consumes": [
                     "application/json"
                 ],
@@ -662,17 +644,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Authentication"
+                ],
+                "summary": "Reset Password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResetPasswordRequest"
+                        }
+                    }
                 ],
-                "summary": "Get All Menus",
                 "responses": {
                     "200": {
-                        "description": "List of menus",
+                        "description": "Password reset successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MenuResponse"
-                            }
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or invalid/expired token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
@@ -682,14 +678,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new menu",
+                "description": "Exchange a refresh token for a new access/refresh token pair. The presented refresh token is invalidated; replaying an already rotated token revokes every token in its family.",
                 "consumes": [
                     "application/json"
                 ],
@@ -697,29 +690,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Authentication"
                 ],
-                "summary": "Create Menu",
+                "summary": "Refresh Tokens",
                 "parameters": [
                     {
-                        "description": "Menu details",
-                        "name": "menu",
+                        "description": "Refresh token",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MenuCreateRequest"
+                            "$ref": "#/definitions/models.RefreshRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Menu created successfully",
+                    "200": {
+                        "description": "New token pair",
                         "schema": {
-                            "$ref": "#/definitions/models.MenuResponse"
+                            "$ref": "#/definitions/models.RefreshResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired, revoked or reused refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Account is disabled",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -733,14 +738,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/menus/{id}": {
+        "/auth/sessions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific menu by its ID",
+                "description": "List the current user's active sessions with the device, user agent and address they were last used from. The session making the request is marked as current.",
                 "consumes": [
                     "application/json"
                 ],
@@ -748,46 +753,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
-                ],
-                "summary": "Get Menu by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Menu ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Authentication"
                 ],
+                "summary": "Get Sessions",
                 "responses": {
                     "200": {
-                        "description": "Menu details",
+                        "description": "Active sessions",
                         "schema": {
-                            "$ref": "#/definitions/models.MenuResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Session"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid ID",
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Menu not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing menu",
+                "description": "Sign out one of the current user's sessions, revoking its refresh token and access tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -795,42 +796,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Authentication"
                 ],
-                "summary": "Update Menu",
+                "summary": "Delete Session",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Menu ID",
+                        "description": "Session ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated menu details",
-                        "name": "menu",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.MenuCreateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Menu updated successfully",
+                        "description": "Session revoked",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Menu not found",
+                        "description": "Session not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -842,14 +840,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a menu by ID",
+            }
+        },
+        "/auth/signup": {
+            "post": {
+                "description": "Register a new customer account with email, password, and profile information. Accounts created here always have the USER role and start unverified; a verification link is emailed. Tokens are only returned when email verification is not required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -857,33 +852,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Authentication"
                 ],
-                "summary": "Delete Menu",
+                "summary": "User Signup",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Menu ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User Registration Details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SignupRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Menu deleted successfully",
+                    "201": {
+                        "description": "User created successfully with authentication token",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.SignupResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid request body or validation error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Menu not found",
+                    "409": {
+                        "description": "Email already exists in the system",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -897,14 +894,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/orderitems": {
+        "/auth/user": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve order items, optionally filtered by order ID",
+                "description": "Get authenticated user profile details (requires valid JWT token)",
                 "consumes": [
                     "application/json"
                 ],
@@ -912,42 +909,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OrderItem"
-                ],
-                "summary": "Get Order Items",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by Order ID",
-                        "name": "order_id",
-                        "in": "query"
-                    }
+                    "Authentication"
                 ],
+                "summary": "Get Current User",
                 "responses": {
                     "200": {
-                        "description": "List of order items",
+                        "description": "User profile details (password field will be empty)",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.OrderItemResponse"
-                            }
+                            "$ref": "#/definitions/models.UserSummary"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Missing or invalid authentication token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found in database",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new order item",
+                "description": "Change the authenticated user's first name, last name or phone. Omitted fields are left unchanged; the same rules as on signup apply.",
                 "consumes": [
                     "application/json"
                 ],
@@ -955,29 +947,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OrderItem"
+                    "Authentication"
                 ],
-                "summary": "Create Order Item",
+                "summary": "Update Current User",
                 "parameters": [
                     {
-                        "description": "Order item details",
-                        "name": "orderitem",
+                        "description": "Fields to change",
+                        "name": "profile",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OrderItemCreateRequest"
+                            "$ref": "#/definitions/models.ProfileUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Order item created successfully",
+                    "200": {
+                        "description": "Updated user profile",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderItemResponse"
+                            "$ref": "#/definitions/models.UserSummary"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -991,14 +995,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/orderitems/{id}": {
-            "get": {
+        "/auth/user/password": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific order item by its ID",
+                "description": "Change the authenticated user's password. The current password is required, the new one follows the same rules as on signup, and every other session is signed out.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1006,46 +1010,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OrderItem"
+                    "Authentication"
                 ],
-                "summary": "Get Order Item by ID",
+                "summary": "Change Password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Order Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangePasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order item details",
+                        "description": "Password changed successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderItemResponse"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token, or wrong current password",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Order item not found",
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update an existing order item",
+            }
+        },
+        "/auth/verify": {
+            "get": {
+                "description": "Confirm an email address using the token from a verification email",
                 "consumes": [
                     "application/json"
                 ],
@@ -1053,42 +1068,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OrderItem"
+                    "Authentication"
                 ],
-                "summary": "Update Order Item",
+                "summary": "Verify Email",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order Item ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Updated order item details",
-                        "name": "orderitem",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.OrderItemCreateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order item updated successfully",
+                        "description": "Email verified successfully",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Order item not found",
+                        "description": "Invalid or expired token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1100,14 +1100,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete an order item by ID",
+            }
+        },
+        "/auth/verify/resend": {
+            "post": {
+                "description": "Send a new verification link to an unverified account. The response is the same whether or not the email is registered.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1115,33 +1112,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OrderItem"
+                    "Authentication"
                 ],
-                "summary": "Delete Order Item",
+                "summary": "Resend Verification Email",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Order Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResendVerificationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order item deleted successfully",
+                        "description": "Verification email sent if the account needs one",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Order item not found",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1155,14 +1148,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders": {
+        "/foods": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all orders",
+                "description": "Retrieve a complete list of all available food items in the restaurant",
                 "consumes": [
                     "application/json"
                 ],
@@ -1170,21 +1163,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Food"
                 ],
-                "summary": "Get All Orders",
+                "summary": "Get All Foods",
                 "responses": {
                     "200": {
-                        "description": "List of orders",
+                        "description": "Array of all food items with details",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.OrderResponse"
+                                "$ref": "#/definitions/models.FoodResponse"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Database error while fetching foods",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1197,7 +1196,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new order",
+                "description": "Create a new food item in the restaurant menu (requires foods:create)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1205,35 +1204,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Food"
                 ],
-                "summary": "Create Order",
+                "summary": "Create Food",
                 "parameters": [
                     {
-                        "description": "Order details",
-                        "name": "order",
+                        "description": "Food item details (name, price, image, menu_id)",
+                        "name": "food",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OrderCreateRequest"
+                            "$ref": "#/definitions/models.FoodCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Order created successfully",
+                        "description": "Food created successfully with generated ID",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderResponse"
+                            "$ref": "#/definitions/models.FoodResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Database error while creating food",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1241,14 +1246,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}": {
+        "/foods/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific order by its ID",
+                "description": "Retrieve detailed information about a specific food item using its unique ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1256,13 +1261,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Food"
                 ],
-                "summary": "Get Order by ID",
+                "summary": "Get Food by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Food MongoDB ObjectID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1270,19 +1276,25 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order details",
+                        "description": "Food item details",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderResponse"
+                            "$ref": "#/definitions/models.FoodResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid MongoDB ObjectID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Order not found",
+                        "description": "Food item not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1295,7 +1307,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing order",
+                "description": "Update an existing food item's information (requires foods:update)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1303,48 +1315,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Food"
                 ],
-                "summary": "Update Order",
+                "summary": "Update Food",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Food MongoDB ObjectID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated order details",
-                        "name": "order",
+                        "description": "Updated food details",
+                        "name": "food",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OrderCreateRequest"
+                            "$ref": "#/definitions/models.FoodCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order updated successfully",
+                        "description": "Food updated successfully",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid ID format or request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Order not found",
+                        "description": "Food item not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Database error while updating food",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1357,7 +1376,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an order by ID",
+                "description": "Permanently delete a food item from the menu (requires foods:delete). Order items that reference the food are handled according to ON_DELETE_FOOD_ORDER_ITEMS and promotions that target it according to ON_DELETE_FOOD_PROMOTIONS; by default the delete is refused with 409 while either exist.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1365,13 +1384,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Food"
                 ],
-                "summary": "Delete Order",
+                "summary": "Delete Food",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Food MongoDB ObjectID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1379,25 +1399,37 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order deleted successfully",
+                        "description": "Food deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid MongoDB ObjectID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Order not found",
+                        "description": "Food item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Still referenced by other records",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Database error while deleting food",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1405,14 +1437,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/tables": {
+        "/foods/{id}/availability": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a food item as available or sold out (requires foods:availability). Unavailable foods cannot be added to orders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Food"
+                ],
+                "summary": "Set Food Availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Food MongoDB ObjectID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New availability",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FoodAvailabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Food availability updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid authentication token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Food item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error while updating food",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all restaurant tables",
+                "description": "Retrieve a list of invoices. Callers without the invoices:any permission only see their own invoices.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1420,16 +1523,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Invoice"
                 ],
-                "summary": "Get All Tables",
+                "summary": "Get All Invoices",
                 "responses": {
                     "200": {
-                        "description": "List of tables",
+                        "description": "List of invoices",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.TableResponse"
+                                "$ref": "#/definitions/models.InvoiceResponse"
                             }
                         }
                     },
@@ -1447,7 +1550,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new restaurant table",
+                "description": "Create a new invoice for an order. Works like POST /orders/{id}/invoice but takes the order ID in the body. The invoice starts as pending; use /invoices/{id}/pay to record the payment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1455,29 +1558,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Invoice"
                 ],
-                "summary": "Create Table",
+                "summary": "Create Invoice",
                 "parameters": [
                     {
-                        "description": "Table details",
-                        "name": "table",
+                        "description": "Invoice details",
+                        "name": "invoice",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TableCreateRequest"
+                            "$ref": "#/definitions/models.InvoiceCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Table created successfully",
+                        "description": "Invoice created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.TableResponse"
+                            "$ref": "#/definitions/models.InvoiceResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad request or order without items",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is not delivered, already has an invoice or uses an exhausted promotion",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1491,14 +1606,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tables/{id}": {
+        "/invoices/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific table by its ID",
+                "description": "Retrieve a specific invoice by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1506,13 +1621,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Invoice"
                 ],
-                "summary": "Get Table by ID",
+                "summary": "Get Invoice by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Table ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1520,9 +1635,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Table details",
+                        "description": "Invoice details",
                         "schema": {
-                            "$ref": "#/definitions/models.TableResponse"
+                            "$ref": "#/definitions/models.InvoiceResponse"
                         }
                     },
                     "400": {
@@ -1532,7 +1647,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Table not found",
+                        "description": "Invoice not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1545,7 +1660,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing table",
+                "description": "Update the payment method and status of an invoice. The status can only move from pending to failed, from failed back to pending and from paid to refunded; use /invoices/{id}/pay to record a payment. The order and total of an invoice cannot be changed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1553,30 +1668,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Invoice"
                 ],
-                "summary": "Update Table",
+                "summary": "Update Invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Table ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated table details",
-                        "name": "table",
+                        "description": "Updated payment details",
+                        "name": "invoice",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TableCreateRequest"
+                            "$ref": "#/definitions/models.InvoiceUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Table updated successfully",
+                        "description": "Invoice updated successfully",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
@@ -1588,7 +1703,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Table not found",
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status change not allowed",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1600,14 +1721,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/invoices/{id}/pay": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a table by ID",
+                "description": "Record the payment of a pending or failed invoice together with an optional tip. The tip is not part of the invoice total; it is credited to staff_id, by default the staff member who created the invoice, and shows up in GET /reports/tips. staff_id must be a user with a staff role and is required for a tip on invoices created without one, such as those created with an API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1615,33 +1738,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Invoice"
                 ],
-                "summary": "Delete Table",
+                "summary": "Pay Invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Table ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Payment method, tip and staff member",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvoicePaymentRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Table deleted successfully",
+                        "description": "Invoice paid",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.InvoiceResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad request or unknown staff member",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Table not found",
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invoice is already paid or refunded",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1654,71 +1792,3958 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Error message here"
-                }
-            }
         },
-        "models.Food": {
-            "type": "object",
-            "required": [
-                "food_image",
-                "menu_id",
-                "name",
-                "price"
-            ],
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "food_image": {
-                    "type": "string",
-                    "example": "https://example.com/images/chicken.jpg"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
-                },
-                "menu_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2,
-                    "example": "Grilled Chicken"
-                },
-                "price": {
+        "/menus": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of all menus",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Get All Menus",
+                "responses": {
+                    "200": {
+                        "description": "List of menus",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MenuResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new menu",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Create Menu",
+                "parameters": [
+                    {
+                        "description": "Menu details",
+                        "name": "menu",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Menu created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/menus/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific menu by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Get Menu by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Menu ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Menu details",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Menu not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing menu",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Update Menu",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Menu ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated menu details",
+                        "name": "menu",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Menu updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Menu not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a menu by ID. Foods on the menu are handled according to ON_DELETE_MENU_FOODS and promotions that target it according to ON_DELETE_MENU_PROMOTIONS; by default the delete is refused with 409 while either exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Delete Menu",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Menu ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Menu deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Menu not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Still referenced by other records",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orderitems": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve order items, optionally filtered by order ID. Callers without the orders:any permission only see items of their own orders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OrderItem"
+                ],
+                "summary": "Get Order Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by Order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of order items",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrderItemResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new order item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OrderItem"
+                ],
+                "summary": "Create Order Item",
+                "parameters": [
+                    {
+                        "description": "Order item details",
+                        "name": "orderitem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderItemCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Order item created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is billed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orderitems/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific order item by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OrderItem"
+                ],
+                "summary": "Get Order Item by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order item details",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing order item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OrderItem"
+                ],
+                "summary": "Update Order Item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated order item details",
+                        "name": "orderitem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderItemCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order item updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order item or order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is billed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an order item by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OrderItem"
+                ],
+                "summary": "Delete Order Item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order item deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is billed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of orders. Callers without the orders:any permission only see their own orders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get All Orders",
+                "responses": {
+                    "200": {
+                        "description": "List of orders",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrderResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new order, optionally together with its items. The order and all items are inserted in one transaction, so either everything is created or nothing is. New orders always start as pending; use /orders/{id}/status to move them on. Creating items requires orderitems:create.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Create Order",
+                "parameters": [
+                    {
+                        "description": "Order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Order created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing orderitems:create permission",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific order with its items, each line total and the order's subtotal, tax, discount and total, all computed by the server",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get Order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order details",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing order's table, service type and guest count. Takeaway orders need no table, and billed or cancelled orders cannot be changed. The status is not changed here; use /orders/{id}/status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Update Order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is billed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an order by ID. Its items are deleted with it and invoices for it are handled according to ON_DELETE_ORDER_INVOICES; by default the delete is refused with 409 while invoices exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Delete Order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Still referenced by other records",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/coupons": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enter a coupon code on an order. The coupon's promotion then discounts the order's matching items when it is totaled and invoiced. Codes are case-insensitive.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Enter Coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coupon code",
+                        "name": "coupon",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Coupon applied",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, or coupon expired or not valid yet",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order or coupon not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is closed or coupon has reached its usage limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/coupons/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a coupon code from an order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Remove Coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Coupon removed",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/invoice": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bill a delivered order: the invoice's lines, subtotal, discounts, service charge, tax and total are copied from the order, the payment starts as pending and the order moves to billed. An order is invoiced once; refunding its invoice does not reopen it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Invoice Order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment method",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invoice created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.InvoiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or order without items",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is not delivered, already has an invoice or uses an exhausted promotion",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/items": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add, change and remove several items of an order in one transaction: if any change fails, none is applied. Adding requires orderitems:create and removing requires orderitems:delete. Returns the order with its resulting items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OrderItem"
+                ],
+                "summary": "Bulk Edit Order Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderItemsBulkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order items updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing orderitems:create or orderitems:delete permission",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order or order item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is billed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move an order to a new status. Allowed transitions are pending to preparing or cancelled, preparing to ready or cancelled, and ready to delivered or cancelled; cancelled is final and delivered orders only move on to billed when they are invoiced through /orders/{id}/invoice. Starting and finishing preparation requires orders:prepare, delivering requires orders:deliver, cancelling a pending order requires orders:cancel and cancelling after preparation has started requires orders:void. The time of each transition is recorded in status_timestamps.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Change Order Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order status changed",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission for this transition",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Illegal status transition",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every promotion, including coupon codes and how often they were used (requires promotions:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Get All Promotions",
+                "responses": {
+                    "200": {
+                        "description": "List of promotions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Promotion"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a percentage, fixed or buy-X-get-Y promotion (requires promotions:manage). Promotions without a code are applied automatically to every matching order; with a code they only apply once the code is entered on the order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Create Promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion details",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Promotion created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Coupon code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific promotion by its ID (requires promotions:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Get Promotion by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a promotion (requires promotions:manage). Its usage count is kept. Orders are discounted by the current rules until they are invoiced; existing invoices keep the discounts they were billed with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Update Promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated promotion details",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Coupon code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a promotion (requires promotions:manage). It stops applying to open orders; invoices keep the discounts they were billed with. Set active to false instead to keep its usage history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Delete Promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/tips": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add up the tips paid in a period by the staff member they went to, for distributing them at the end of a shift (requires tips:report). The period defaults to the current day in the restaurant's time zone up to now.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Tip Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the period (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, exclusive (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tips per staff member",
+                        "schema": {
+                            "$ref": "#/definitions/models.TipReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every role with the permissions it grants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get All Roles",
+                "responses": {
+                    "200": {
+                        "description": "List of roles",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a new staff role and the permissions it grants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Create Role",
+                "parameters": [
+                    {
+                        "description": "Role details",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Role already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every permission that can be granted to a role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get Permissions",
+                "responses": {
+                    "200": {
+                        "description": "List of permissions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the description, permissions and two-factor requirement of a role. The role name cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Update Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated role details",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a custom role. Built-in roles and roles still assigned to users cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Delete Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or built-in role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Role is still assigned to users",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/servicecharges": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every service charge rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceCharge"
+                ],
+                "summary": "Get Service Charge Rules",
+                "responses": {
+                    "200": {
+                        "description": "List of service charge rules",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ServiceChargeRule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Charge a service charge on orders for at least min_guests guests (requires servicecharges:manage). The party size is the order's guest_count or, without one, its table's capacity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceCharge"
+                ],
+                "summary": "Create Service Charge Rule",
+                "parameters": [
+                    {
+                        "description": "Service charge rule details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceChargeRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Service charge rule created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceChargeRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/servicecharges/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a service charge rule (requires servicecharges:manage). Orders are charged by the current rules until they are invoiced; existing invoices keep the service charge they were billed with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceCharge"
+                ],
+                "summary": "Update Service Charge Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service charge rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated service charge rule details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceChargeRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service charge rule updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service charge rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a service charge rule (requires servicecharges:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceCharge"
+                ],
+                "summary": "Delete Service Charge Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service charge rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service charge rule deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service charge rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tables": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of all restaurant tables",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Get All Tables",
+                "responses": {
+                    "200": {
+                        "description": "List of tables",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TableResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new restaurant table",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Create Table",
+                "parameters": [
+                    {
+                        "description": "Table details",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TableCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Table created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.TableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific table by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Get Table by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Table details",
+                        "schema": {
+                            "$ref": "#/definitions/models.TableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing table",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Update Table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated table details",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TableCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Table updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a table by ID. Orders at the table are handled according to ON_DELETE_TABLE_ORDERS; by default the delete is refused with 409 while they exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Delete Table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Table deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Still referenced by other records",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxclasses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every tax class with its dine-in and takeaway rates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TaxClass"
+                ],
+                "summary": "Get Tax Classes",
+                "responses": {
+                    "200": {
+                        "description": "List of tax classes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaxClass"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a tax class that foods can be assigned to (requires taxes:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TaxClass"
+                ],
+                "summary": "Create Tax Class",
+                "parameters": [
+                    {
+                        "description": "Tax class details",
+                        "name": "taxclass",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxClassRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tax class created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxClass"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tax class already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxclasses/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the name, rates or pricing of a tax class (requires taxes:manage). Orders are taxed at the current rates until they are invoiced; existing invoices keep the rates they were billed at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TaxClass"
+                ],
+                "summary": "Update Tax Class",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tax class ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated tax class details",
+                        "name": "taxclass",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxClassRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tax class updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax class not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another tax class has this name",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a tax class (requires taxes:manage). Foods in the class are handled according to ON_DELETE_TAX_CLASS_FOODS; by default the delete is refused with 409 while they exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TaxClass"
+                ],
+                "summary": "Delete Tax Class",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tax class ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tax class deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax class not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Still referenced by other records",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all user accounts, optionally filtered by role (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get All Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by role",
+                        "name": "user_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users (password fields will be empty)",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a user account with any role, e.g. to provision staff. Set email_verified to skip email verification (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create User",
+                "parameters": [
+                    {
+                        "description": "User details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or unknown role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/2fa/reset": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a user's TOTP secret and recovery codes, e.g. after a lost device. If their role requires two-factor authentication they enroll again at next login (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset User Two-Factor Authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication reset",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/revoke-sessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sign a user out of every device by revoking all of their access and refresh tokens (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke User Sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a user's role. The user's existing sessions are revoked so the new role takes effect immediately (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserRoleUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User role updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable or re-enable a user account. Disabling revokes all of the user's sessions (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserStatusUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User status updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clear the failed login attempts recorded for a user so a locked out account can log in again (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unlock User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User unlocked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a user's email address as verified without a verification email, e.g. for internal staff accounts (requires users:manage)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Verify User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User verified successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "last_used_ip": {
+                    "type": "string",
+                    "example": "10.0.0.12"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kitchen display 1"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "orders:read",
+                        "orders:update",
+                        "orders:any"
+                    ]
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "rk_3f2a9c4b"
+                },
+                "revoked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "revoked_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKeyCreateResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/models.APIKey"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "rk_3f2a9c4b..."
+                },
+                "message": {
+                    "type": "string",
+                    "example": "API key created successfully, store it now as it will not be shown again"
+                }
+            }
+        },
+        "models.APIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Kitchen display 1"
+                },
+                "permissions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "orders:read",
+                        "orders:update",
+                        "orders:any"
+                    ]
+                }
+            }
+        },
+        "models.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "newpassword456"
+                }
+            }
+        },
+        "models.CouponRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "SUMMER10"
+                }
+            }
+        },
+        "models.DiscountLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SUMMER10"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Happy hour"
+                },
+                "promotion_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439031"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Error message here"
+                }
+            }
+        },
+        "models.Food": {
+            "type": "object",
+            "required": [
+                "food_image",
+                "menu_id",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "food_image": {
+                    "type": "string",
+                    "example": "https://example.com/images/chicken.jpg"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "menu_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Grilled Chicken"
+                },
+                "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax_class_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.FoodAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "available"
+            ],
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.FoodCreateRequest": {
+            "type": "object",
+            "required": [
+                "food_image",
+                "menu_id",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "food_image": {
+                    "type": "string",
+                    "example": "https://example.com/images/chicken.jpg"
+                },
+                "menu_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Grilled Chicken"
+                },
+                "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax_class_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                }
+            }
+        },
+        "models.FoodResponse": {
+            "type": "object",
+            "properties": {
+                "food": {
+                    "$ref": "#/definitions/models.Food"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Food created successfully"
+                }
+            }
+        },
+        "models.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                }
+            }
+        },
+        "models.Invoice": {
+            "type": "object",
+            "required": [
+                "order_id",
+                "payment_method",
+                "payment_status"
+            ],
+            "properties": {
+                "amount_paid": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439020"
+                },
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiscountLine"
+                    }
+                },
+                "guest_count": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvoiceLine"
+                    }
+                },
+                "order_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "paid_at": {
+                    "type": "string",
+                    "example": "2024-01-01T21:00:00Z"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "mobile_payment"
+                    ],
+                    "example": "credit_card"
+                },
+                "payment_status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "failed",
+                        "refunded"
+                    ],
+                    "example": "paid"
+                },
+                "service_charge": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "service_charge_rate": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "service_charge_rule": {
+                    "type": "string",
+                    "example": "Large parties"
+                },
+                "service_type": {
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway"
+                    ],
+                    "example": "dine_in"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxLine"
+                    }
+                },
+                "tip": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tip_staff_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439020"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439019"
+                }
+            }
+        },
+        "models.InvoiceCreateRequest": {
+            "type": "object",
+            "required": [
+                "order_id",
+                "payment_method"
+            ],
+            "properties": {
+                "order_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "mobile_payment"
+                    ],
+                    "example": "credit_card"
+                }
+            }
+        },
+        "models.InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "food_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439013"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Grilled Chicken"
+                },
+                "order_item_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439017"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "tax": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax_class_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                },
+                "tax_rate": {
                     "type": "number",
-                    "example": 15.99
+                    "example": 8.5
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.InvoicePaymentRequest": {
+            "type": "object",
+            "required": [
+                "payment_method"
+            ],
+            "properties": {
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "mobile_payment"
+                    ],
+                    "example": "credit_card"
+                },
+                "staff_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439020"
+                },
+                "tip": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.InvoiceResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439018"
+                },
+                "invoice": {
+                    "$ref": "#/definitions/models.Invoice"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Invoice fetched successfully"
+                }
+            }
+        },
+        "models.InvoiceUpdateRequest": {
+            "type": "object",
+            "required": [
+                "payment_method",
+                "payment_status"
+            ],
+            "properties": {
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "mobile_payment"
+                    ],
+                    "example": "credit_card"
+                },
+                "payment_status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "failed",
+                        "refunded"
+                    ],
+                    "example": "refunded"
+                }
+            }
+        },
+        "models.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "EdDSA"
+                },
+                "crv": {
+                    "type": "string",
+                    "example": "Ed25519"
+                },
+                "e": {
+                    "type": "string",
+                    "example": "AQAB"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "3f2a9c4b1d7e6a05"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "OKP"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JWKSResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JWK"
+                    }
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "enrollment_required": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Login successful"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "3f9a1-7c2e0"
+                    ]
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "two_factor_required": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSummary"
+                }
+            }
+        },
+        "models.Menu": {
+            "type": "object",
+            "required": [
+                "category",
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "Main Course"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Dinner Menu"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.MenuCreateRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "Main Course"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Dinner Menu"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.MenuResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439014"
+                },
+                "menu": {
+                    "$ref": "#/definitions/models.Menu"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Menu fetched successfully"
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "coupon_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "SUMMER10"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "guest_count": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "order_date": {
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "service_type": {
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway"
+                    ],
+                    "example": "dine_in"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "preparing",
+                        "ready",
+                        "delivered",
+                        "cancelled"
+                    ],
+                    "example": "pending"
+                },
+                "status_timestamps": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "pending": "2024-01-01T12:00:00Z"
+                    }
+                },
+                "table_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439019"
+                }
+            }
+        },
+        "models.OrderCreateRequest": {
+            "type": "object",
+            "properties": {
+                "guest_count": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderLineRequest"
+                    }
+                },
+                "service_type": {
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway"
+                    ],
+                    "example": "dine_in"
+                },
+                "table_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                }
+            }
+        },
+        "models.OrderDetail": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "coupon_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "SUMMER10"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "guest_count": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderLine"
+                    }
+                },
+                "order_date": {
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "party_size": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "service_type": {
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway"
+                    ],
+                    "example": "dine_in"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "preparing",
+                        "ready",
+                        "delivered",
+                        "cancelled"
+                    ],
+                    "example": "pending"
+                },
+                "status_timestamps": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "pending": "2024-01-01T12:00:00Z"
+                    }
+                },
+                "table_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.OrderTotals"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439019"
+                }
+            }
+        },
+        "models.OrderDetailResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439015"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Order created successfully"
+                },
+                "order": {
+                    "$ref": "#/definitions/models.OrderDetail"
+                }
+            }
+        },
+        "models.OrderInvoiceRequest": {
+            "type": "object",
+            "required": [
+                "payment_method"
+            ],
+            "properties": {
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "mobile_payment"
+                    ],
+                    "example": "credit_card"
+                }
+            }
+        },
+        "models.OrderItem": {
+            "type": "object",
+            "required": [
+                "food_id",
+                "order_id",
+                "quantity",
+                "unit_price"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "food_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439013"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Grilled Chicken"
+                },
+                "order_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "tax_class_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
                 },
                 "updated_at": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.OrderItemCreateRequest": {
+            "type": "object",
+            "required": [
+                "food_id",
+                "order_id",
+                "quantity"
+            ],
+            "properties": {
+                "food_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439013"
+                },
+                "order_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439017"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Order item fetched successfully"
+                },
+                "order_item": {
+                    "$ref": "#/definitions/models.OrderItem"
                 }
             }
         },
-        "models.FoodCreateRequest": {
+        "models.OrderItemsBulkRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderLineRequest"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439017"
+                    ]
+                },
+                "update": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderLineUpdate"
+                    }
+                }
+            }
+        },
+        "models.OrderLine": {
             "type": "object",
             "required": [
-                "food_image",
-                "menu_id",
-                "name",
-                "price"
+                "food_id",
+                "order_id",
+                "quantity",
+                "unit_price"
             ],
             "properties": {
-                "food_image": {
+                "category": {
                     "type": "string",
-                    "example": "https://example.com/images/chicken.jpg"
+                    "example": "Drinks"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "food_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439013"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/money.Money"
                 },
                 "menu_id": {
                     "type": "string",
@@ -1726,189 +5751,511 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2,
                     "example": "Grilled Chicken"
                 },
-                "price": {
+                "order_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "tax": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax_class_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                },
+                "tax_rate": {
                     "type": "number",
-                    "example": 15.99
+                    "example": 8.5
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
                 }
             }
         },
-        "models.FoodResponse": {
+        "models.OrderLineRequest": {
             "type": "object",
+            "required": [
+                "food_id",
+                "quantity"
+            ],
             "properties": {
-                "food": {
-                    "$ref": "#/definitions/models.Food"
+                "food_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439013"
                 },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.OrderLineUpdate": {
+            "type": "object",
+            "required": [
+                "id",
+                "quantity"
+            ],
+            "properties": {
                 "id": {
                     "type": "string",
                     "example": "507f1f77bcf86cd799439011"
                 },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 3
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439015"
+                },
                 "message": {
                     "type": "string",
-                    "example": "Food created successfully"
+                    "example": "Order fetched successfully"
+                },
+                "order": {
+                    "$ref": "#/definitions/models.Order"
                 }
             }
         },
-        "models.Invoice": {
+        "models.OrderStatusRequest": {
             "type": "object",
             "required": [
-                "order_id",
-                "payment_method",
-                "payment_status",
-                "total_amount"
+                "status"
             ],
             "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "preparing",
+                        "ready",
+                        "delivered",
+                        "cancelled"
+                    ],
+                    "example": "preparing"
+                }
+            }
+        },
+        "models.OrderTotals": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiscountLine"
+                    }
+                },
+                "service_charge": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "service_charge_rate": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "service_charge_rule": {
+                    "type": "string",
+                    "example": "Large parties"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxLine"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.OrderUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "guest_count": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "service_type": {
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway"
+                    ],
+                    "example": "dine_in"
+                },
+                "table_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439012"
+                }
+            }
+        },
+        "models.ProfileUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+1234567890"
+                }
+            }
+        },
+        "models.Promotion": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "buy_quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Drinks"
+                    ]
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SUMMER10"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
                 },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Half price drinks from 17:00 to 19:00"
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "19:00"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "food_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439013"
+                    ]
+                },
+                "get_quantity": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "id": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
+                    "example": "507f1f77bcf86cd799439031"
+                },
+                "menu_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439011"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Happy hour"
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 50
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed",
+                        "buy_x_get_y"
+                    ],
+                    "example": "percentage"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "usage_limit": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "used_count": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.PromotionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "buy_quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Drinks"
+                    ]
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3,
+                    "example": "SUMMER10"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Half price drinks from 17:00 to 19:00"
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "19:00"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "food_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439013"
+                    ]
+                },
+                "get_quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "menu_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439011"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Happy hour"
                 },
-                "order_id": {
+                "percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 50
+                },
+                "start_time": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439012"
+                    "example": "17:00"
                 },
-                "payment_method": {
+                "starts_at": {
                     "type": "string",
-                    "enum": [
-                        "cash",
-                        "credit_card",
-                        "debit_card",
-                        "mobile_payment"
-                    ],
-                    "example": "credit_card"
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "payment_status": {
+                "type": {
                     "type": "string",
                     "enum": [
-                        "pending",
-                        "paid",
-                        "failed",
-                        "refunded"
+                        "percentage",
+                        "fixed",
+                        "buy_x_get_y"
                     ],
-                    "example": "paid"
+                    "example": "percentage"
                 },
-                "total_amount": {
-                    "type": "number",
-                    "example": 45.99
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                "usage_limit": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
                 }
             }
         },
-        "models.InvoiceCreateRequest": {
+        "models.RefreshRequest": {
             "type": "object",
             "required": [
-                "order_id",
-                "payment_method",
-                "payment_status",
-                "total_amount"
+                "refresh_token"
             ],
             "properties": {
-                "order_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439012"
-                },
-                "payment_method": {
-                    "type": "string",
-                    "enum": [
-                        "cash",
-                        "credit_card",
-                        "debit_card",
-                        "mobile_payment"
-                    ],
-                    "example": "credit_card"
-                },
-                "payment_status": {
+                "refresh_token": {
                     "type": "string",
-                    "enum": [
-                        "pending",
-                        "paid",
-                        "failed",
-                        "refunded"
-                    ],
-                    "example": "paid"
-                },
-                "total_amount": {
-                    "type": "number",
-                    "example": 45.99
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 }
             }
         },
-        "models.InvoiceResponse": {
+        "models.RefreshResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "message": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439018"
+                    "example": "Token refreshed successfully"
                 },
-                "invoice": {
-                    "$ref": "#/definitions/models.Invoice"
+                "refresh_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 },
-                "message": {
+                "token": {
                     "type": "string",
-                    "example": "Invoice fetched successfully"
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 }
             }
         },
-        "models.LoginRequest": {
+        "models.ResendVerificationRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "email"
             ],
             "properties": {
                 "email": {
                     "type": "string",
                     "example": "john.doe@example.com"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
                 }
             }
         },
-        "models.LoginResponse": {
+        "models.ResetPasswordRequest": {
             "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
             "properties": {
-                "message": {
+                "password": {
                     "type": "string",
-                    "example": "Login successful"
+                    "minLength": 6,
+                    "example": "newpassword123"
                 },
                 "token": {
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-                },
-                "user": {
-                    "$ref": "#/definitions/models.UserSummary"
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
                 }
             }
         },
-        "models.Menu": {
+        "models.Role": {
             "type": "object",
             "required": [
-                "category",
                 "name"
             ],
             "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "Main Course"
+                "built_in": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "created_at": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
                 },
-                "end_date": {
+                "description": {
                     "type": "string",
-                    "example": "2024-12-31T23:59:59Z"
+                    "example": "Kitchen staff"
                 },
                 "id": {
                     "type": "string",
@@ -1916,11 +6263,23 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string",
-                    "example": "Dinner Menu"
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "CHEF"
                 },
-                "start_date": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "orders:read",
+                        "orders:update"
+                    ]
+                },
+                "require_two_factor": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "updated_at": {
                     "type": "string",
@@ -1928,144 +6287,72 @@ const docTemplate = `{
                 }
             }
         },
-        "models.MenuCreateRequest": {
+        "models.RoleRequest": {
             "type": "object",
             "required": [
-                "category",
                 "name"
             ],
             "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "Main Course"
-                },
-                "end_date": {
+                "description": {
                     "type": "string",
-                    "example": "2024-12-31T23:59:59Z"
+                    "example": "Kitchen staff"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Dinner Menu"
-                },
-                "start_date": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                }
-            }
-        },
-        "models.MenuResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439014"
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "CHEF"
                 },
-                "menu": {
-                    "$ref": "#/definitions/models.Menu"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "orders:read",
+                        "orders:update"
+                    ]
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Menu fetched successfully"
+                "require_two_factor": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "models.Order": {
+        "models.ServiceChargeRule": {
             "type": "object",
-            "required": [
-                "status",
-                "table_id"
-            ],
             "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
                 },
                 "id": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
+                    "example": "507f1f77bcf86cd799439041"
                 },
-                "order_date": {
-                    "type": "string",
-                    "example": "2024-01-01T12:00:00Z"
+                "min_guests": {
+                    "type": "integer",
+                    "example": 8
                 },
-                "status": {
+                "name": {
                     "type": "string",
-                    "enum": [
-                        "pending",
-                        "preparing",
-                        "ready",
-                        "delivered",
-                        "cancelled"
-                    ],
-                    "example": "pending"
+                    "example": "Large parties"
                 },
-                "table_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439012"
+                "rate": {
+                    "type": "number",
+                    "example": 12.5
                 },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                }
-            }
-        },
-        "models.OrderCreateRequest": {
-            "type": "object",
-            "required": [
-                "status",
-                "table_id"
-            ],
-            "properties": {
-                "status": {
+                "service_type": {
                     "type": "string",
                     "enum": [
-                        "pending",
-                        "preparing",
-                        "ready",
-                        "delivered",
-                        "cancelled"
+                        "dine_in",
+                        "takeaway"
                     ],
-                    "example": "pending"
-                },
-                "table_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439012"
-                }
-            }
-        },
-        "models.OrderItem": {
-            "type": "object",
-            "required": [
-                "food_id",
-                "order_id",
-                "quantity",
-                "unit_price"
-            ],
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "food_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439013"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
-                },
-                "order_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439012"
-                },
-                "quantity": {
-                    "type": "integer",
-                    "minimum": 1,
-                    "example": 2
-                },
-                "unit_price": {
-                    "type": "number",
-                    "example": 15.99
+                    "example": "dine_in"
                 },
                 "updated_at": {
                     "type": "string",
@@ -2073,63 +6360,81 @@ const docTemplate = `{
                 }
             }
         },
-        "models.OrderItemCreateRequest": {
+        "models.ServiceChargeRuleRequest": {
             "type": "object",
             "required": [
-                "food_id",
-                "order_id",
-                "quantity",
-                "unit_price"
+                "name"
             ],
             "properties": {
-                "food_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439013"
-                },
-                "order_id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439012"
+                "active": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "quantity": {
+                "min_guests": {
                     "type": "integer",
-                    "minimum": 1,
-                    "example": 2
+                    "minimum": 0,
+                    "example": 8
                 },
-                "unit_price": {
-                    "type": "number",
-                    "example": 15.99
-                }
-            }
-        },
-        "models.OrderItemResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
+                "name": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439017"
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Large parties"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Order item fetched successfully"
+                "rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 12.5
                 },
-                "order_item": {
-                    "$ref": "#/definitions/models.OrderItem"
+                "service_type": {
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway"
+                    ],
+                    "example": "dine_in"
                 }
             }
         },
-        "models.OrderResponse": {
+        "models.Session": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "current": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "device_name": {
+                    "type": "string",
+                    "example": "Front counter iPad"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-01-09T00:00:00Z"
+                },
                 "id": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439015"
+                    "example": "507f1f77bcf86cd799439011"
                 },
-                "message": {
+                "ip": {
                     "type": "string",
-                    "example": "Order fetched successfully"
+                    "example": "203.0.113.7"
                 },
-                "order": {
-                    "$ref": "#/definitions/models.Order"
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2024-01-02T00:00:00Z"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
                 }
             }
         },
@@ -2140,8 +6445,7 @@ const docTemplate = `{
                 "first_name",
                 "last_name",
                 "password",
-                "phone",
-                "user_type"
+                "phone"
             ],
             "properties": {
                 "email": {
@@ -2168,14 +6472,6 @@ const docTemplate = `{
                 "phone": {
                     "type": "string",
                     "example": "+1234567890"
-                },
-                "user_type": {
-                    "type": "string",
-                    "enum": [
-                        "USER",
-                        "ADMIN"
-                    ],
-                    "example": "USER"
                 }
             }
         },
@@ -2186,6 +6482,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "User created successfully"
                 },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
                 "token": {
                     "type": "string",
                     "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
@@ -2195,6 +6495,26 @@ const docTemplate = `{
                 }
             }
         },
+        "models.StaffTips": {
+            "type": "object",
+            "properties": {
+                "invoices": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jane Smith"
+                },
+                "staff_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439020"
+                },
+                "tips": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
         "models.SuccessResponse": {
             "type": "object",
             "properties": {
@@ -2274,6 +6594,207 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TaxClass": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Beer, wine and spirits"
+                },
+                "dine_in_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 20
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                },
+                "inclusive": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "Alcohol"
+                },
+                "takeaway_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 20
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.TaxClassRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Beer, wine and spirits"
+                },
+                "dine_in_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 20
+                },
+                "inclusive": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "Alcohol"
+                },
+                "takeaway_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 20
+                }
+            }
+        },
+        "models.TaxLine": {
+            "type": "object",
+            "properties": {
+                "inclusive": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alcohol"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 20
+                },
+                "tax": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "tax_class_id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439021"
+                },
+                "taxable": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.TipReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "staff": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StaffTips"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-01-02T00:00:00Z"
+                },
+                "total": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.TwoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "models.TwoFactorEnableResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Two-factor authentication enabled"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "3f9a1-7c2e0"
+                    ]
+                }
+            }
+        },
+        "models.TwoFactorEnrollRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "models.TwoFactorLoginRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "models.TwoFactorSetupResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/Restaurant%20API:john.doe@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=Restaurant+API"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "required": [
@@ -2289,10 +6810,18 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
                 },
+                "disabled": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "email": {
                     "type": "string",
                     "example": "john.doe@example.com"
                 },
+                "email_verified": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "first_name": {
                     "type": "string",
                     "maxLength": 100,
@@ -2318,13 +6847,9 @@ const docTemplate = `{
                     "type": "string",
                     "example": "+1234567890"
                 },
-                "refresh_token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-                },
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                "totp_enabled": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "updated_at": {
                     "type": "string",
@@ -2332,14 +6857,78 @@ const docTemplate = `{
                 },
                 "user_type": {
                     "type": "string",
-                    "enum": [
-                        "USER",
-                        "ADMIN"
-                    ],
                     "example": "USER"
                 }
             }
         },
+        "models.UserCreateRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password",
+                "phone",
+                "user_type"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jane.smith@example.com"
+                },
+                "email_verified": {
+                    "description": "EmailVerified pre-verifies the address, e.g. for internal staff accounts",
+                    "type": "boolean",
+                    "example": true
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Jane"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Smith"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "password123"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+1234567890"
+                },
+                "user_type": {
+                    "type": "string",
+                    "example": "WAITER"
+                }
+            }
+        },
+        "models.UserRoleUpdateRequest": {
+            "type": "object",
+            "required": [
+                "user_type"
+            ],
+            "properties": {
+                "user_type": {
+                    "type": "string",
+                    "example": "CHEF"
+                }
+            }
+        },
+        "models.UserStatusUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "models.UserSummary": {
             "type": "object",
             "properties": {
@@ -2361,19 +6950,29 @@ const docTemplate = `{
                 },
                 "user_type": {
                     "type": "string",
-                    "enum": [
-                        "USER",
-                        "ADMIN"
-                    ],
                     "example": "USER"
                 }
             }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1599
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "BearerAuth": {
+            "description": "Access token as \"Bearer \u003ctoken\u003e\". The legacy \"token\" header is still accepted.",
             "type": "apiKey",
-            "name": "token",
+            "name": "Authorization",
             "in": "header"
         }
     }
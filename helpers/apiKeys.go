@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// APIKeyPrefix starts every API key so that it can be told apart from a JWT.
+const APIKeyPrefix = "rk_"
+
+// apiKeyUsageInterval limits how often last-used tracking writes to the
+// database for a busy device.
+const apiKeyUsageInterval = time.Minute
+
+var ErrInvalidAPIKey = errors.New("invalid or revoked API key")
+
+func getAPIKeyCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "apikeys")
+}
+
+// IsAPIKey reports whether credential looks like an API key rather than a JWT.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// GenerateAPIKey returns a new API key and the prefix shown to admins to
+// identify it.
+func GenerateAPIKey() (key string, prefix string, err error) {
+	random, err := GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	key = APIKeyPrefix + random
+	return key, key[:len(APIKeyPrefix)+8], nil
+}
+
+// AuthenticateAPIKey looks up an active API key and records that it was used
+// from ip. It returns ErrInvalidAPIKey for unknown, revoked or expired keys.
+func AuthenticateAPIKey(ctx context.Context, key string, ip string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	err := getAPIKeyCollection().FindOne(ctx, bson.M{"key_hash": HashToken(key), "revoked": false}).Decode(&apiKey)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	_, err = getAPIKeyCollection().UpdateOne(ctx,
+		bson.M{"_id": apiKey.ID, "$or": bson.A{
+			bson.M{"last_used_at": bson.M{"$exists": false}},
+			bson.M{"last_used_at": bson.M{"$lt": now.Add(-apiKeyUsageInterval)}},
+		}},
+		bson.M{"$set": bson.M{"last_used_at": now, "last_used_ip": ip}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
@@ -1,15 +1,170 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	PasswordHasherBcrypt   = "bcrypt"
+	PasswordHasherArgon2id = "argon2id"
+
+	argon2idPrefix = "$argon2id$"
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+)
+
+// argon2Params are the argon2id cost parameters encoded in a PHC string.
+type argon2Params struct {
+	Memory      uint32
+	Time        uint32
+	Parallelism uint8
+}
+
+// The password hashing policy. New hashes use passwordHasher; hashes made with
+// another algorithm or other costs are replaced on the next successful login.
+var (
+	passwordHasher = PasswordHasherBcrypt
+	bcryptCost     = 14
+	argon2Policy   = argon2Params{Memory: 64 * 1024, Time: 3, Parallelism: 2}
+)
+
+// SetupPasswordHashing loads the password hashing policy from the
+// environment: PASSWORD_HASHER (bcrypt or argon2id), BCRYPT_COST, and
+// ARGON2_MEMORY (KiB), ARGON2_TIME and ARGON2_PARALLELISM.
+func SetupPasswordHashing() error {
+	if hasher := os.Getenv("PASSWORD_HASHER"); hasher != "" {
+		if hasher != PasswordHasherBcrypt && hasher != PasswordHasherArgon2id {
+			return fmt.Errorf("unsupported PASSWORD_HASHER %q", hasher)
+		}
+		passwordHasher = hasher
+	}
+
+	if value := os.Getenv("BCRYPT_COST"); value != "" {
+		cost, err := strconv.Atoi(value)
+		if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		bcryptCost = cost
+	}
+
+	params := []struct {
+		name string
+		bits int
+		dest func(uint64)
+	}{
+		{"ARGON2_MEMORY", 32, func(v uint64) { argon2Policy.Memory = uint32(v) }},
+		{"ARGON2_TIME", 32, func(v uint64) { argon2Policy.Time = uint32(v) }},
+		{"ARGON2_PARALLELISM", 8, func(v uint64) { argon2Policy.Parallelism = uint8(v) }},
+	}
+	for _, param := range params {
+		value := os.Getenv(param.name)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(value, 10, param.bits)
+		if err != nil || v == 0 {
+			return fmt.Errorf("%s must be a positive integer", param.name)
+		}
+		param.dest(v)
+	}
+
+	fmt.Printf("✅ Password hashing configured: %s\n", passwordHasher)
+	return nil
+}
+
+// HashPassword hashes password with the configured algorithm. argon2id hashes
+// are PHC strings starting with $argon2id$; anything else is bcrypt.
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	if passwordHasher == PasswordHasherArgon2id {
+		return hashArgon2id(password, argon2Policy)
+	}
+
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
 	return string(bytes), err
 }
 
 func VerifyPassword(hashedPassword, password string) bool {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		params, salt, key, err := decodeArgon2id(hashedPassword)
+		if err != nil {
+			return false
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(computed, key) == 1
+	}
+
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// PasswordNeedsRehash reports whether hashedPassword was made with a different
+// algorithm or cost than the current policy, so that it should be replaced
+// once the plaintext password is known.
+func PasswordNeedsRehash(hashedPassword string) bool {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		if passwordHasher != PasswordHasherArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2id(hashedPassword)
+		return err != nil || params != argon2Policy
+	}
+
+	if passwordHasher != PasswordHasherBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != bcryptCost
+}
+
+func hashArgon2id(password string, params argon2Params) (string, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Parallelism, argon2KeySize)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, params.Memory, params.Time, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// decodeArgon2id parses a PHC string of the form
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>.
+func decodeArgon2id(hashedPassword string) (params argon2Params, salt []byte, key []byte, err error) {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters")
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	return params, salt, key, nil
+}
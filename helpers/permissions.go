@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// roleCacheTTL bounds how long a role change can take to reach other
+// instances; changes made through this instance invalidate the cache at once.
+const roleCacheTTL = 30 * time.Second
+
+type cachedRole struct {
+	permissions []string
+	loadedAt    time.Time
+}
+
+var (
+	roleCacheMu sync.Mutex
+	roleCache   = map[string]cachedRole{}
+)
+
+// GetRolePermissions returns the permissions granted to the named role. An
+// unknown role has no permissions.
+func GetRolePermissions(ctx context.Context, roleName string) ([]string, error) {
+	roleCacheMu.Lock()
+	cached, ok := roleCache[roleName]
+	roleCacheMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < roleCacheTTL {
+		return cached.permissions, nil
+	}
+
+	var role models.Role
+	err := database.GetCollection(database.Client, "roles").FindOne(ctx, bson.M{"name": roleName}).Decode(&role)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	roleCacheMu.Lock()
+	roleCache[roleName] = cachedRole{permissions: role.Permissions, loadedAt: time.Now()}
+	roleCacheMu.Unlock()
+
+	return role.Permissions, nil
+}
+
+// InvalidateRoleCache drops all cached role permissions.
+func InvalidateRoleCache() {
+	roleCacheMu.Lock()
+	roleCache = map[string]cachedRole{}
+	roleCacheMu.Unlock()
+}
+
+// HasPermission reports whether permissions grants permission.
+func HasPermission(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission || p == models.PermissionAll {
+			return true
+		}
+	}
+	return false
+}
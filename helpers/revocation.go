@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"basic-backend/database"
+	"basic-backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func getRevokedTokenCollection() *mongo.Collection {
+	return database.GetCollection(database.Client, "revokedtokens")
+}
+
+// RevokeToken adds a single access token to the revocation list until it
+// would have expired.
+func RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	entry := models.RevokedToken{
+		ID:        primitive.NewObjectID(),
+		TokenID:   tokenID,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	_, err := getRevokedTokenCollection().InsertOne(ctx, entry)
+	return err
+}
+
+// RevokeTokenFamily invalidates every access token issued to the session
+// identified by familyID.
+func RevokeTokenFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	entry := models.RevokedToken{
+		ID:        primitive.NewObjectID(),
+		FamilyID:  familyID,
+		ExpiresAt: now.Add(AccessTokenLifetime),
+		CreatedAt: now,
+	}
+
+	_, err := getRevokedTokenCollection().InsertOne(ctx, entry)
+	return err
+}
+
+// RevokeAllUserTokens invalidates every access token issued to the user up to
+// now, to the millisecond.
+func RevokeAllUserTokens(ctx context.Context, uid string) error {
+	now := time.Now()
+	entry := models.RevokedToken{
+		ID:            primitive.NewObjectID(),
+		UserID:        uid,
+		RevokedBefore: &now,
+		ExpiresAt:     now.Add(AccessTokenLifetime),
+		CreatedAt:     now,
+	}
+
+	_, err := getRevokedTokenCollection().InsertOne(ctx, entry)
+	return err
+}
+
+// IsTokenRevoked reports whether the access token described by claims is on
+// the revocation list.
+func IsTokenRevoked(ctx context.Context, claims *SignedDetails) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	conditions := bson.A{
+		bson.M{"user_id": claims.Uid, "revoked_before": bson.M{"$gte": issuedAt}},
+	}
+	if claims.ID != "" {
+		conditions = append(conditions, bson.M{"token_id": claims.ID})
+	}
+	if claims.FamilyID != "" {
+		conditions = append(conditions, bson.M{"family_id": claims.FamilyID})
+	}
+
+	count, err := getRevokedTokenCollection().CountDocuments(ctx, bson.M{"$or": conditions})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,275 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const developmentSecret = "insecure-development-secret"
+
+// SigningKey is a key that tokens are signed or verified with. Key is the
+// HMAC secret or the private key for the current signing key, and the HMAC
+// secret or public key for keys that are only kept for verification.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Key       interface{}
+	PublicKey crypto.PublicKey
+}
+
+// verificationKey returns the key used to check signatures made with k.
+func (k *SigningKey) verificationKey() interface{} {
+	if k.PublicKey != nil {
+		return k.PublicKey
+	}
+	return k.Key
+}
+
+var (
+	currentSigningKey *SigningKey
+	verificationKeys  = map[string]*SigningKey{}
+)
+
+// IsProduction reports whether the server runs in production mode, either
+// APP_ENV=production or GIN_MODE=release.
+func IsProduction() bool {
+	return os.Getenv("APP_ENV") == "production" || os.Getenv("GIN_MODE") == "release"
+}
+
+// SetupSigningKeys loads the JWT signing key and the keys that are still
+// accepted for verification from the environment. It must run after the
+// environment is loaded and before any token is issued or validated.
+//
+// JWT_ALGORITHM selects HS256 (default), RS256 or EdDSA. HS256 signs with
+// JWT_SECRET; RS256 and EdDSA sign with the PEM private key in
+// JWT_PRIVATE_KEY_FILE. During a rotation the previous keys stay valid by
+// listing them in JWT_PREVIOUS_SECRETS (comma separated secrets) or
+// JWT_PREVIOUS_PUBLIC_KEY_FILES (comma separated PEM files).
+func SetupSigningKeys() error {
+	algorithm := os.Getenv("JWT_ALGORITHM")
+	if algorithm == "" {
+		algorithm = jwt.SigningMethodHS256.Alg()
+	}
+
+	var key *SigningKey
+	var err error
+	switch algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			if IsProduction() {
+				return errors.New("JWT_SECRET must be set in production")
+			}
+			log.Println("⚠️  JWT_SECRET is not set, using an insecure development secret")
+			secret = developmentSecret
+		}
+		key = hmacKey(secret)
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		path := os.Getenv("JWT_PRIVATE_KEY_FILE")
+		if path == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_FILE must be set for %s", algorithm)
+		}
+		key, err = loadPrivateKey(path, algorithm)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported JWT_ALGORITHM %q", algorithm)
+	}
+
+	keys := map[string]*SigningKey{key.ID: key}
+
+	for _, secret := range splitList(os.Getenv("JWT_PREVIOUS_SECRETS")) {
+		previous := hmacKey(secret)
+		keys[previous.ID] = previous
+	}
+
+	for _, path := range splitList(os.Getenv("JWT_PREVIOUS_PUBLIC_KEY_FILES")) {
+		previous, err := loadPublicKey(path)
+		if err != nil {
+			return err
+		}
+		keys[previous.ID] = previous
+	}
+
+	currentSigningKey = key
+	verificationKeys = keys
+
+	fmt.Printf("✅ JWT signing key configured: %s (kid %s, %d verification keys)\n", key.Method.Alg(), key.ID, len(keys))
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// keyID derives a stable key ID from the key material so that every instance
+// configured with the same key advertises the same kid.
+func keyID(material []byte) string {
+	sum := sha256.Sum256(material)
+	return hex.EncodeToString(sum[:8])
+}
+
+func hmacKey(secret string) *SigningKey {
+	return &SigningKey{
+		ID:     keyID([]byte(secret)),
+		Method: jwt.SigningMethodHS256,
+		Key:    []byte(secret),
+	}
+}
+
+func publicKeyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return keyID(der), nil
+}
+
+func loadPrivateKey(path string, algorithm string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWT private key: %w", err)
+	}
+
+	key := &SigningKey{}
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JWT private key: %w", err)
+		}
+		key.Method = jwt.SigningMethodRS256
+		key.Key = privateKey
+		key.PublicKey = &privateKey.PublicKey
+	default:
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JWT private key: %w", err)
+		}
+		key.Method = jwt.SigningMethodEdDSA
+		key.Key = privateKey
+		key.PublicKey = privateKey.(ed25519.PrivateKey).Public()
+	}
+
+	key.ID, err = publicKeyID(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func loadPublicKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWT public key: %w", err)
+	}
+
+	key := &SigningKey{}
+	if publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		key.Method = jwt.SigningMethodRS256
+		key.PublicKey = publicKey
+	} else if publicKey, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		key.Method = jwt.SigningMethodEdDSA
+		key.PublicKey = publicKey
+	} else {
+		return nil, fmt.Errorf("parsing JWT public key %s: unsupported key type", path)
+	}
+
+	key.ID, err = publicKeyID(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// signToken signs claims with the current signing key and records its kid in
+// the token header.
+func signToken(claims jwt.Claims) (string, error) {
+	if currentSigningKey == nil {
+		return "", errors.New("JWT signing key is not configured")
+	}
+
+	token := jwt.NewWithClaims(currentSigningKey.Method, claims)
+	token.Header["kid"] = currentSigningKey.ID
+	return token.SignedString(currentSigningKey.Key)
+}
+
+// lookupVerificationKey is the jwt.Keyfunc used to validate tokens. Tokens
+// issued before key IDs were introduced have no kid and are checked against
+// every key for their algorithm.
+func lookupVerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid != "" {
+		key, ok := verificationKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if key.Method.Alg() != token.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key.verificationKey(), nil
+	}
+
+	var keySet jwt.VerificationKeySet
+	for _, key := range verificationKeys {
+		if key.Method.Alg() == token.Method.Alg() {
+			keySet.Keys = append(keySet.Keys, key.verificationKey())
+		}
+	}
+	if len(keySet.Keys) == 0 {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return keySet, nil
+}
+
+// JWKS returns the public verification keys as a JSON Web Key Set. HMAC
+// secrets are never published.
+func JWKS() []map[string]string {
+	keys := []map[string]string{}
+	for _, key := range verificationKeys {
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": key.Method.Alg(),
+				"kid": key.ID,
+				"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "OKP",
+				"use": "sig",
+				"alg": key.Method.Alg(),
+				"kid": key.ID,
+				"crv": "Ed25519",
+				"x":   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i]["kid"] < keys[j]["kid"] })
+	return keys
+}
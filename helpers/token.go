@@ -1,52 +1,80 @@
 package helpers
 
 import (
-	"os"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	AccessTokenType    = "access"
+	RefreshTokenType   = "refresh"
+	ChallengeTokenType = "2fa_challenge"
+
+	AccessTokenLifetime    = time.Hour * 24
+	RefreshTokenLifetime   = time.Hour * 168
+	ChallengeTokenLifetime = time.Minute * 5
+)
+
+// Token times are kept to the millisecond, the precision of BSON dates, so
+// that a token issued just before RevokeAllUserTokens is revoked with the
+// others.
+func init() {
+	jwt.TimePrecision = time.Millisecond
+}
+
 type SignedDetails struct {
 	Email     string
 	FirstName string
 	LastName  string
 	UserType  string
-	jwt.StandardClaims
+	Uid       string
+	FamilyID  string
+	TokenType string
+	jwt.RegisteredClaims
 }
 
-var SECRET_KEY string
-
-func init() {
-	SECRET_KEY = os.Getenv("JWT_SECRET")
-	if SECRET_KEY == "" {
-		SECRET_KEY = "default_secret_key_change_in_production"
-	}
-}
-
-func GenerateAllTokens(email string, firstName string, lastName string, userType string) (signedToken string, signedRefreshToken string, err error) {
+// GenerateAllTokens mints an access token and a refresh token for the user.
+// The refresh token belongs to familyID, which is shared by every token
+// obtained by rotating it so that a replay can revoke the whole chain.
+func GenerateAllTokens(email string, firstName string, lastName string, userType string, uid string, familyID string) (signedToken string, signedRefreshToken string, err error) {
+	now := time.Now()
 	claims := &SignedDetails{
 		Email:     email,
 		FirstName: firstName,
 		LastName:  lastName,
 		UserType:  userType,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
+		Uid:       uid,
+		FamilyID:  familyID,
+		TokenType: AccessTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        primitive.NewObjectID().Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenLifetime)),
 		},
 	}
 
 	refreshClaims := &SignedDetails{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 168).Unix(),
+		Uid:       uid,
+		FamilyID:  familyID,
+		TokenType: RefreshTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        primitive.NewObjectID().Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(RefreshTokenLifetime)),
 		},
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
+	token, err := signToken(claims)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(SECRET_KEY))
+	refreshToken, err := signToken(refreshClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -54,13 +82,30 @@ func GenerateAllTokens(email string, firstName string, lastName string, userType
 	return token, refreshToken, nil
 }
 
+// GenerateChallengeToken mints a short-lived token proving that the user has
+// passed the password step of a two-factor login. It cannot be used to
+// authenticate requests.
+func GenerateChallengeToken(uid string) (string, error) {
+	now := time.Now()
+	claims := &SignedDetails{
+		Uid:       uid,
+		TokenType: ChallengeTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        primitive.NewObjectID().Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ChallengeTokenLifetime)),
+		},
+	}
+
+	return signToken(claims)
+}
+
 func ValidateToken(signedToken string) (claims *SignedDetails, msg string) {
 	token, err := jwt.ParseWithClaims(
 		signedToken,
 		&SignedDetails{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(SECRET_KEY), nil
-		},
+		lookupVerificationKey,
+		jwt.WithExpirationRequired(),
 	)
 
 	if err != nil {
@@ -74,10 +119,22 @@ func ValidateToken(signedToken string) (claims *SignedDetails, msg string) {
 		return
 	}
 
-	if claims.ExpiresAt < time.Now().Unix() {
-		msg = "token is expired"
-		return
+	return claims, msg
+}
+
+// GenerateRandomToken returns a URL-safe random token with 256 bits of
+// entropy.
+func GenerateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(b), nil
+}
 
-	return claims, msg
+// HashToken returns the hex encoded SHA-256 digest of a token so that it can
+// be stored and looked up without keeping the raw value.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
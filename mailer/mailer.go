@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Default is the mailer used by the application. It is set by Setup.
+var Default Mailer = &LogMailer{}
+
+// Setup configures Default from the environment. MAILER selects the
+// implementation: "smtp" sends through SMTP_HOST/SMTP_PORT, anything else logs
+// messages, appending them to MAILER_LOG_FILE when it is set.
+func Setup() {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			port = 587
+		}
+		Default = &SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+	default:
+		Default = &LogMailer{Path: os.Getenv("MAILER_LOG_FILE")}
+	}
+
+	fmt.Printf("✅ Mailer configured: %T\n", Default)
+}
+
+// Send delivers msg with Default.
+func Send(ctx context.Context, msg Message) error {
+	return Default.Send(ctx, msg)
+}
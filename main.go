@@ -1,8 +1,12 @@
 package main
 
 import (
+	"basic-backend/controllers"
 	"basic-backend/database"
 	_ "basic-backend/docs" // Import generated docs
+	"basic-backend/helpers"
+	"basic-backend/mailer"
+	"basic-backend/money"
 	"basic-backend/routes"
 	"fmt"
 	"log"
@@ -31,7 +35,8 @@ import (
 
 // @securityDefinitions.apikey BearerAuth
 // @in header
-// @name token
+// @name Authorization
+// @description Access token as "Bearer <token>". The legacy "token" header is still accepted.
 
 func main() {
 	// Load environment variables
@@ -40,8 +45,46 @@ func main() {
 		log.Println("No .env file found, using system environment variables")
 	}
 
+	// Load the JWT signing and verification keys
+	if err := helpers.SetupSigningKeys(); err != nil {
+		log.Fatal("Failed to configure JWT signing keys: ", err)
+	}
+
+	// Load the password hashing policy
+	if err := helpers.SetupPasswordHashing(); err != nil {
+		log.Fatal("Failed to configure password hashing: ", err)
+	}
+
+	// Load the tax and service charge rates
+	if err := controllers.SetupBilling(); err != nil {
+		log.Fatal("Failed to configure billing: ", err)
+	}
+
+	// Load the currency, rounding mode and JSON format of amounts
+	if err := money.Setup(); err != nil {
+		log.Fatal("Failed to configure money: ", err)
+	}
+
+	// Load what happens to references when a document is deleted
+	if err := controllers.SetupDeleteRules(); err != nil {
+		log.Fatal("Failed to configure delete rules: ", err)
+	}
+
 	// Connect to MongoDB
 	database.ConnectDB()
+	database.CreateIndexes()
+	database.MigrateFieldNames()
+	database.MigrateMoneyFields()
+
+	// Configure outgoing email
+	mailer.Setup()
+
+	// Create the built-in roles and provision the first admin account if configured
+	controllers.SeedDefaultRoles()
+	controllers.BackfillEmailVerified()
+	controllers.BackfillFoodAvailability()
+	controllers.MigrateSessions()
+	controllers.BootstrapAdmin()
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -68,6 +111,12 @@ func main() {
 	routes.TableRoutes(router)
 	routes.OrderItemRoutes(router)
 	routes.InvoiceRoutes(router)
+	routes.RoleRoutes(router)
+	routes.APIKeyRoutes(router)
+	routes.TaxClassRoutes(router)
+	routes.PromotionRoutes(router)
+	routes.ServiceChargeRoutes(router)
+	routes.ReportRoutes(router)
 
 	// Swagger documentation route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
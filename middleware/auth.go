@@ -2,42 +2,160 @@ package middleware
 
 import (
 	"basic-backend/helpers"
+	"context"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const authRealm = "Restaurant API"
+
+// challenge aborts the request with status and an RFC 6750 WWW-Authenticate
+// header. errCode is omitted when the request carried no credentials at all.
+func challenge(c *gin.Context, status int, errCode string, description string) {
+	header := `Bearer realm="` + authRealm + `"`
+	if errCode != "" {
+		header += `, error="` + errCode + `", error_description="` + strings.ReplaceAll(description, `"`, "'") + `"`
+	}
+
+	c.Header("WWW-Authenticate", header)
+	c.JSON(status, gin.H{"error": description})
+	c.Abort()
+}
+
+// extractToken returns the access token or API key from the Authorization:
+// Bearer header, the legacy token header, the X-API-Key header or, when
+// enabled, the auth cookie, in that order. ok is false when an Authorization
+// header is present but does not use the Bearer scheme.
+func extractToken(c *gin.Context) (token string, ok bool) {
+	if authorization := c.GetHeader("Authorization"); authorization != "" {
+		scheme, credentials, found := strings.Cut(authorization, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") {
+			return "", false
+		}
+		return strings.TrimSpace(credentials), true
+	}
+
+	if token := c.GetHeader("token"); token != "" {
+		return token, true
+	}
+
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key, true
+	}
+
+	if helpers.AuthCookieEnabled() {
+		if token, err := c.Cookie(helpers.AuthCookieName()); err == nil {
+			return token, true
+		}
+	}
+
+	return "", true
+}
+
 func Authentication() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientToken := c.Request.Header.Get("token")
+		clientToken, ok := extractToken(c)
+		if !ok {
+			challenge(c, http.StatusBadRequest, "invalid_request", "Authorization header must use the Bearer scheme")
+			return
+		}
+
 		if clientToken == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "No Authorization header provided"})
-			c.Abort()
+			challenge(c, http.StatusUnauthorized, "", "No Authorization header provided")
+			return
+		}
+
+		if helpers.IsAPIKey(clientToken) {
+			authenticateDevice(c, clientToken)
 			return
 		}
 
 		claims, errMsg := helpers.ValidateToken(clientToken)
 		if errMsg != "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": errMsg})
+			challenge(c, http.StatusUnauthorized, "invalid_token", errMsg)
+			return
+		}
+
+		// Tokens minted before token types were introduced have neither a
+		// type nor a user ID, so they could never be revoked. They are
+		// refused and their holders have to sign in again.
+		if claims.TokenType != helpers.AccessTokenType || claims.Uid == "" {
+			challenge(c, http.StatusUnauthorized, "invalid_token", "Only access tokens can be used for authentication")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		revoked, err := helpers.IsTokenRevoked(ctx, claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking token revocation"})
 			c.Abort()
 			return
 		}
 
+		if revoked {
+			challenge(c, http.StatusUnauthorized, "invalid_token", "token has been revoked")
+			return
+		}
+
+		permissions, err := helpers.GetRolePermissions(ctx, claims.UserType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Set("auth_type", "user")
 		c.Set("email", claims.Email)
 		c.Set("first_name", claims.FirstName)
 		c.Set("last_name", claims.LastName)
 		c.Set("user_type", claims.UserType)
+		c.Set("uid", claims.Uid)
+		c.Set("family_id", claims.FamilyID)
+		c.Set("token_id", claims.ID)
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
+		c.Set("permissions", permissions)
 
 		c.Next()
 	}
 }
 
-func RequireAdmin() gin.HandlerFunc {
+// authenticateDevice authenticates the request with an API key. Devices have
+// no user claims; the key's ID and name are exposed as device_id and
+// device_name and its scopes as permissions.
+func authenticateDevice(c *gin.Context, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	apiKey, err := helpers.AuthenticateAPIKey(ctx, key, c.ClientIP())
+	if err == helpers.ErrInvalidAPIKey {
+		challenge(c, http.StatusUnauthorized, "invalid_token", err.Error())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking API key"})
+		c.Abort()
+		return
+	}
+
+	c.Set("auth_type", "device")
+	c.Set("device_id", apiKey.ID.Hex())
+	c.Set("device_name", apiKey.Name)
+	c.Set("permissions", apiKey.Permissions)
+
+	c.Next()
+}
+
+// RequirePermission aborts with 403 unless the authenticated user's role
+// grants permission. It must run after Authentication.
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userType := c.GetString("user_type")
-		if userType != "ADMIN" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
+		if !helpers.HasPermission(c.GetStringSlice("permissions"), permission) {
+			challenge(c, http.StatusForbidden, "insufficient_scope", "Permission "+permission+" required")
 			return
 		}
 		c.Next()
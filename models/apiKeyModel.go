@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKey authenticates a device such as a POS terminal or kitchen display.
+// Only the hash of the key is stored; Prefix is kept so admins can tell keys
+// apart. The key grants exactly Permissions, independent of any role.
+type APIKey struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	Name        string             `bson:"name" json:"name" example:"Kitchen display 1"`
+	Prefix      string             `bson:"prefix" json:"prefix" example:"rk_3f2a9c4b"`
+	KeyHash     string             `bson:"key_hash" json:"-"`
+	Permissions []string           `bson:"permissions" json:"permissions" example:"orders:read,orders:update,orders:any"`
+	CreatedBy   string             `bson:"created_by" json:"created_by" example:"507f1f77bcf86cd799439011"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	ExpiresAt   *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty" example:"2025-01-01T00:00:00Z"`
+	LastUsedAt  *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty" example:"2024-01-01T12:00:00Z"`
+	LastUsedIP  string             `bson:"last_used_ip,omitempty" json:"last_used_ip,omitempty" example:"10.0.0.12"`
+	Revoked     bool               `bson:"revoked" json:"revoked" example:"false"`
+	RevokedAt   *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
@@ -1,17 +1,23 @@
 package models
 
 import (
+	"basic-backend/money"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Food is a dish on the menu. Only available foods can be ordered; order items
+// copy the name, price and tax class at the time they are ordered. Foods
+// without a tax class are taxed at the default rate.
 type Food struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
-	Name      string             `json:"name" validate:"required,min=2,max=100" example:"Grilled Chicken"`
-	Price     float64            `json:"price" validate:"required,gt=0" example:"15.99"`
-	FoodImage string             `json:"food_image" validate:"required" example:"https://example.com/images/chicken.jpg"`
-	MenuID    string             `json:"menu_id" validate:"required" example:"507f1f77bcf86cd799439011"`
-	CreatedAt time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time          `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	Name       string             `bson:"name" json:"name" validate:"required,min=2,max=100" example:"Grilled Chicken"`
+	Price      money.Money        `bson:"price" json:"price" validate:"required,gt=0"`
+	FoodImage  string             `bson:"food_image" json:"food_image" validate:"required" example:"https://example.com/images/chicken.jpg"`
+	MenuID     string             `bson:"menu_id" json:"menu_id" validate:"required" example:"507f1f77bcf86cd799439011"`
+	Available  bool               `bson:"available" json:"available" example:"true"`
+	TaxClassID string             `bson:"tax_class_id" json:"tax_class_id,omitempty" example:"507f1f77bcf86cd799439021"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
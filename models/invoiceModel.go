@@ -1,17 +1,87 @@
 package models
 
 import (
+	"basic-backend/money"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	PaymentStatusPending  = "pending"
+	PaymentStatusPaid     = "paid"
+	PaymentStatusFailed   = "failed"
+	PaymentStatusRefunded = "refunded"
+)
+
+// PaymentTransitions lists, for each payment status, the statuses an invoice
+// can be moved to by updating it. Statuses without an entry are final.
+// Invoices only become paid when the payment is recorded with its tip.
+var PaymentTransitions = map[string][]string{
+	PaymentStatusPending: {PaymentStatusFailed},
+	PaymentStatusFailed:  {PaymentStatusPending},
+	PaymentStatusPaid:    {PaymentStatusRefunded},
+}
+
+// Invoice bills an order. Lines and amounts are copied from the order when the
+// invoice is created, so later changes to the order do not alter it. An order
+// can only have one invoice that has not been refunded.
+//
+// Tip is added when the invoice is paid and goes to the staff member
+// TipStaffID. It is not part of TotalAmount; AmountPaid is both together.
 type Invoice struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
-	OrderID       string             `json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
-	PaymentMethod string             `json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
-	TotalAmount   float64            `json:"total_amount" validate:"required,gt=0" example:"45.99"`
-	PaymentStatus string             `json:"payment_status" validate:"required" example:"paid" enums:"pending,paid,failed,refunded"`
-	CreatedAt     time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt     time.Time          `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	OrderID           string             `bson:"order_id" json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
+	ServiceType       string             `bson:"service_type" json:"service_type" example:"dine_in" enums:"dine_in,takeaway"`
+	PaymentMethod     string             `bson:"payment_method" json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
+	Lines             []InvoiceLine      `bson:"lines" json:"lines"`
+	Subtotal          money.Money        `bson:"subtotal" json:"subtotal"`
+	GuestCount        int                `bson:"guest_count" json:"guest_count" example:"8"`
+	ServiceCharge     money.Money        `bson:"service_charge" json:"service_charge"`
+	ServiceChargeRate float64            `bson:"service_charge_rate" json:"service_charge_rate" example:"12.5"`
+	ServiceChargeRule string             `bson:"service_charge_rule,omitempty" json:"service_charge_rule,omitempty" example:"Large parties"`
+	Tax               money.Money        `bson:"tax" json:"tax"`
+	Taxes             []TaxLine          `bson:"taxes" json:"taxes"`
+	Discount          money.Money        `bson:"discount" json:"discount"`
+	Discounts         []DiscountLine     `bson:"discounts" json:"discounts"`
+	TotalAmount       money.Money        `bson:"total_amount" json:"total_amount"`
+	PaymentStatus     string             `bson:"payment_status" json:"payment_status" validate:"required" example:"paid" enums:"pending,paid,failed,refunded"`
+	Tip               money.Money        `bson:"tip" json:"tip"`
+	TipStaffID        string             `bson:"tip_staff_id,omitempty" json:"tip_staff_id,omitempty" example:"507f1f77bcf86cd799439020"`
+	AmountPaid        money.Money        `bson:"amount_paid" json:"amount_paid"`
+	PaidAt            *time.Time         `bson:"paid_at,omitempty" json:"paid_at,omitempty" example:"2024-01-01T21:00:00Z"`
+	UserID            string             `bson:"user_id" json:"user_id" example:"507f1f77bcf86cd799439019"`
+	CreatedBy         string             `bson:"created_by" json:"created_by" example:"507f1f77bcf86cd799439020"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// TipReport adds up the tips paid between From and To by staff member.
+type TipReport struct {
+	From  time.Time   `json:"from" example:"2024-01-01T00:00:00Z"`
+	To    time.Time   `json:"to" example:"2024-01-02T00:00:00Z"`
+	Staff []StaffTips `json:"staff"`
+	Total money.Money `json:"total"`
+}
+
+// StaffTips is what one staff member was tipped on Invoices invoices.
+type StaffTips struct {
+	StaffID  string      `bson:"staff_id" json:"staff_id" example:"507f1f77bcf86cd799439020"`
+	Name     string      `bson:"name" json:"name" example:"Jane Smith"`
+	Invoices int         `bson:"invoices" json:"invoices" example:"7"`
+	Tips     money.Money `bson:"tips" json:"tips"`
+}
+
+// InvoiceLine is an order item as it was billed.
+type InvoiceLine struct {
+	OrderItemID string      `bson:"order_item_id" json:"order_item_id" example:"507f1f77bcf86cd799439017"`
+	FoodID      string      `bson:"food_id" json:"food_id" example:"507f1f77bcf86cd799439013"`
+	Name        string      `bson:"name" json:"name" example:"Grilled Chicken"`
+	Quantity    int         `bson:"quantity" json:"quantity" example:"2"`
+	UnitPrice   money.Money `bson:"unit_price" json:"unit_price"`
+	LineTotal   money.Money `bson:"line_total" json:"line_total"`
+	Discount    money.Money `bson:"discount" json:"discount"`
+	TaxClassID  string      `bson:"tax_class_id" json:"tax_class_id,omitempty" example:"507f1f77bcf86cd799439021"`
+	TaxRate     float64     `bson:"tax_rate" json:"tax_rate" example:"8.5"`
+	Tax         money.Money `bson:"tax" json:"tax"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoginAttempt tracks consecutive failed logins for a key such as
+// "account:<email>" or "ip:<address>".
+type LoginAttempt struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key           string             `bson:"key" json:"key"`
+	Failures      int                `bson:"failures" json:"failures"`
+	LastFailureAt time.Time          `bson:"last_failure_at" json:"last_failure_at"`
+	LockedUntil   time.Time          `bson:"locked_until" json:"locked_until"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+}
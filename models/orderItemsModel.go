@@ -1,17 +1,23 @@
 package models
 
 import (
+	"basic-backend/money"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// OrderItem is a line of an order. Name, UnitPrice and TaxClassID are copied
+// from the food when the item is created so that later menu changes do not
+// alter the order.
 type OrderItem struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
-	OrderID   string             `json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
-	FoodID    string             `json:"food_id" validate:"required" example:"507f1f77bcf86cd799439013"`
-	Quantity  int                `json:"quantity" validate:"required,min=1" example:"2"`
-	UnitPrice float64            `json:"unit_price" validate:"required,gt=0" example:"15.99"`
-	CreatedAt time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time          `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	OrderID    string             `bson:"order_id" json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
+	FoodID     string             `bson:"food_id" json:"food_id" validate:"required" example:"507f1f77bcf86cd799439013"`
+	Name       string             `bson:"name" json:"name" example:"Grilled Chicken"`
+	TaxClassID string             `bson:"tax_class_id" json:"tax_class_id,omitempty" example:"507f1f77bcf86cd799439021"`
+	Quantity   int                `bson:"quantity" json:"quantity" validate:"required,min=1,max=1000" example:"2"`
+	UnitPrice  money.Money        `bson:"unit_price" json:"unit_price" validate:"required,gt=0"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
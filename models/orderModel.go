@@ -1,16 +1,99 @@
 package models
 
 import (
+	"basic-backend/money"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPreparing = "preparing"
+	OrderStatusReady     = "ready"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusBilled    = "billed"
+)
+
+// OrderTransitions lists, for each status, the statuses an order can move to
+// and the permission each transition requires. Statuses without an entry are
+// final. Orders only become billed when an invoice is created for them.
+var OrderTransitions = map[string]map[string]string{
+	OrderStatusPending: {
+		OrderStatusPreparing: "orders:prepare",
+		OrderStatusCancelled: "orders:cancel",
+	},
+	OrderStatusPreparing: {
+		OrderStatusReady:     "orders:prepare",
+		OrderStatusCancelled: "orders:void",
+	},
+	OrderStatusReady: {
+		OrderStatusDelivered: "orders:deliver",
+		OrderStatusCancelled: "orders:void",
+	},
+	OrderStatusDelivered: {
+		OrderStatusBilled: "invoices:create",
+	},
+}
+
+// Order is a table's order. Status only changes through OrderTransitions, and
+// StatusTimestamps records when the order entered each status. CouponCodes are
+// the promotion codes entered for the order. GuestCount is the size of the
+// party, if known.
 type Order struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
-	TableID   string             `json:"table_id" validate:"required" example:"507f1f77bcf86cd799439012"`
-	OrderDate time.Time          `json:"order_date" example:"2024-01-01T12:00:00Z"`
-	Status    string             `json:"status" validate:"required" example:"pending" enums:"pending,preparing,ready,delivered,cancelled"`
-	CreatedAt time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time          `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ID               primitive.ObjectID   `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	TableID          string               `bson:"table_id" json:"table_id" validate:"required_unless=ServiceType takeaway" example:"507f1f77bcf86cd799439012"`
+	ServiceType      string               `bson:"service_type" json:"service_type" validate:"omitempty,oneof=dine_in takeaway" example:"dine_in" enums:"dine_in,takeaway"`
+	GuestCount       int                  `bson:"guest_count" json:"guest_count" validate:"min=0" example:"4"`
+	OrderDate        time.Time            `bson:"order_date" json:"order_date" example:"2024-01-01T12:00:00Z"`
+	Status           string               `bson:"status" json:"status" validate:"required" example:"pending" enums:"pending,preparing,ready,delivered,cancelled"`
+	UserID           string               `bson:"user_id" json:"user_id" example:"507f1f77bcf86cd799439019"`
+	CouponCodes      []string             `bson:"coupon_codes,omitempty" json:"coupon_codes,omitempty" example:"SUMMER10"`
+	CreatedAt        time.Time            `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt        time.Time            `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	StatusTimestamps map[string]time.Time `bson:"status_timestamps,omitempty" json:"status_timestamps,omitempty" swaggertype:"object,string" example:"pending:2024-01-01T12:00:00Z"`
+}
+
+// OrderDetail is an order together with its items and the totals computed
+// from them. PartySize is the order's guest count or, without one, the
+// capacity of its table, and decides which service charge rule applies.
+type OrderDetail struct {
+	Order     `bson:",inline"`
+	PartySize int         `bson:"party_size" json:"party_size" example:"8"`
+	Items     []OrderLine `bson:"items" json:"items"`
+	Totals    OrderTotals `bson:"totals" json:"totals"`
+}
+
+// OrderLine is an order item with the amount it adds to the order, the
+// discount promotions give on it and the tax charged on the rest. LineTotal is
+// in the food's own pricing, so it already contains the tax if the tax class
+// is inclusive. MenuID and Category are those of the food's menu and decide
+// which promotions apply.
+type OrderLine struct {
+	OrderItem `bson:",inline"`
+	MenuID    string      `bson:"menu_id" json:"menu_id,omitempty" example:"507f1f77bcf86cd799439011"`
+	Category  string      `bson:"category" json:"category,omitempty" example:"Drinks"`
+	LineTotal money.Money `bson:"line_total" json:"line_total"`
+	Discount  money.Money `bson:"discount" json:"discount"`
+	TaxRate   float64     `bson:"tax_rate" json:"tax_rate" example:"8.5"`
+	Tax       money.Money `bson:"tax" json:"tax"`
+}
+
+// OrderTotals is what an order costs. Total is Subtotal minus Discount, plus
+// ServiceCharge and the tax that is not already included in the prices. Tax
+// is all tax on the order, inclusive or not, and Taxes breaks it down by tax
+// class. Discounts breaks Discount down by promotion. ServiceChargeRate is the
+// rate the service charge was charged at and ServiceChargeRule the name of the
+// rule it came from, if any.
+type OrderTotals struct {
+	Subtotal          money.Money    `bson:"subtotal" json:"subtotal"`
+	ServiceCharge     money.Money    `bson:"service_charge" json:"service_charge"`
+	ServiceChargeRate float64        `bson:"service_charge_rate" json:"service_charge_rate" example:"12.5"`
+	ServiceChargeRule string         `bson:"service_charge_rule,omitempty" json:"service_charge_rule,omitempty" example:"Large parties"`
+	Tax               money.Money    `bson:"tax" json:"tax"`
+	Taxes             []TaxLine      `bson:"taxes" json:"taxes"`
+	Discount          money.Money    `bson:"discount" json:"discount"`
+	Discounts         []DiscountLine `bson:"discounts" json:"discounts"`
+	Total             money.Money    `bson:"total" json:"total"`
 }
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"basic-backend/money"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PromotionTypeBuyXGetY   = "buy_x_get_y"
+)
+
+// Promotion is a discount on orders. A percentage promotion takes Percent off
+// each matching line, a fixed one takes Amount off the matching lines
+// together, and a buy-X-get-Y one makes GetQuantity of every BuyQuantity +
+// GetQuantity matching units free, cheapest first.
+//
+// Lines match when their food, menu or menu category is listed, or always if
+// none are. A line is only discounted if it was ordered between StartsAt and
+// EndsAt and, when set, on one of Days between StartTime and EndTime in the
+// restaurant's time zone. Promotions without a Code apply automatically; the
+// others only to orders the code was entered on. UsedCount counts the
+// invoices a promotion was applied to and is capped at UsageLimit unless it
+// is 0.
+type Promotion struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439031"`
+	Name        string             `bson:"name" json:"name" example:"Happy hour"`
+	Description string             `bson:"description" json:"description" example:"Half price drinks from 17:00 to 19:00"`
+	Type        string             `bson:"type" json:"type" example:"percentage" enums:"percentage,fixed,buy_x_get_y"`
+	Percent     float64            `bson:"percent" json:"percent" example:"50"`
+	Amount      money.Money        `bson:"amount" json:"amount"`
+	BuyQuantity int                `bson:"buy_quantity" json:"buy_quantity" example:"2"`
+	GetQuantity int                `bson:"get_quantity" json:"get_quantity" example:"1"`
+	FoodIDs     []string           `bson:"food_ids" json:"food_ids" example:"507f1f77bcf86cd799439013"`
+	MenuIDs     []string           `bson:"menu_ids" json:"menu_ids" example:"507f1f77bcf86cd799439011"`
+	Categories  []string           `bson:"categories" json:"categories" example:"Drinks"`
+	Days        []int              `bson:"days" json:"days" example:"1,2,3,4,5"`
+	StartTime   string             `bson:"start_time" json:"start_time" example:"17:00"`
+	EndTime     string             `bson:"end_time" json:"end_time" example:"19:00"`
+	StartsAt    *time.Time         `bson:"starts_at" json:"starts_at" example:"2024-01-01T00:00:00Z"`
+	EndsAt      *time.Time         `bson:"ends_at" json:"ends_at" example:"2024-12-31T23:59:59Z"`
+	Code        string             `bson:"code,omitempty" json:"code,omitempty" example:"SUMMER10"`
+	UsageLimit  int                `bson:"usage_limit" json:"usage_limit" example:"100"`
+	UsedCount   int                `bson:"used_count" json:"used_count" example:"12"`
+	Active      bool               `bson:"active" json:"active" example:"true"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// Exhausted reports whether the promotion has reached its usage limit.
+func (p Promotion) Exhausted() bool {
+	return p.UsageLimit > 0 && p.UsedCount >= p.UsageLimit
+}
+
+// Expired reports whether the promotion's validity period has ended at t.
+func (p Promotion) Expired(t time.Time) bool {
+	return p.EndsAt != nil && !t.Before(*p.EndsAt)
+}
+
+// DiscountLine is the discount one promotion gave on an order or invoice.
+type DiscountLine struct {
+	PromotionID string      `bson:"promotion_id" json:"promotion_id" example:"507f1f77bcf86cd799439031"`
+	Name        string      `bson:"name" json:"name" example:"Happy hour"`
+	Code        string      `bson:"code,omitempty" json:"code,omitempty" example:"SUMMER10"`
+	Amount      money.Money `bson:"amount" json:"amount"`
+}
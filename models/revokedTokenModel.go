@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevokedToken is an entry in the access token revocation list. An entry
+// revokes a single token by its ID, every token issued to a session by its
+// family ID, or every token of a user issued at or before RevokedBefore.
+// Entries expire once the tokens they cover would have expired anyway.
+type RevokedToken struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TokenID       string             `bson:"token_id,omitempty" json:"token_id,omitempty"`
+	FamilyID      string             `bson:"family_id,omitempty" json:"family_id,omitempty"`
+	UserID        string             `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	RevokedBefore *time.Time         `bson:"revoked_before,omitempty" json:"revoked_before,omitempty"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
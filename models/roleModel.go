@@ -0,0 +1,142 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PermissionAll grants every permission.
+const PermissionAll = "*"
+
+// Permissions lists every permission a role can be granted. The orders:any and
+// invoices:any permissions lift the restriction to the caller's own documents.
+// orders:prepare, orders:deliver, orders:cancel and orders:void guard the
+// order status transitions in OrderTransitions. orderitems:price allows
+// setting an item's price instead of taking it from the food. taxes:manage
+// allows changing the tax classes, promotions:manage the promotions and
+// coupons and servicecharges:manage the service charge rules. tips:report
+// allows reading the tips of every staff member.
+var Permissions = []string{
+	"foods:create", "foods:update", "foods:delete", "foods:availability",
+	"menus:create", "menus:update", "menus:delete",
+	"tables:create", "tables:update", "tables:delete",
+	"orders:read", "orders:create", "orders:update", "orders:delete", "orders:any",
+	"orders:prepare", "orders:deliver", "orders:cancel", "orders:void",
+	"orderitems:read", "orderitems:create", "orderitems:update", "orderitems:delete", "orderitems:price",
+	"invoices:read", "invoices:create", "invoices:update", "invoices:any",
+	"users:manage", "roles:manage", "apikeys:manage", "taxes:manage",
+	"promotions:manage", "servicecharges:manage", "tips:report",
+}
+
+// Role grants a set of permissions to the users whose user_type names it.
+// RequireTwoFactor makes TOTP enrollment mandatory for those users.
+type Role struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	Name             string             `bson:"name" json:"name" validate:"required,min=2,max=50" example:"CHEF"`
+	Description      string             `bson:"description" json:"description" example:"Kitchen staff"`
+	Permissions      []string           `bson:"permissions" json:"permissions" example:"orders:read,orders:update"`
+	RequireTwoFactor bool               `bson:"require_two_factor" json:"require_two_factor" example:"false"`
+	BuiltIn          bool               `bson:"built_in" json:"built_in" example:"true"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// DefaultRoles are created on startup when missing. Existing roles are never
+// overwritten so that admins can tune them.
+var DefaultRoles = []Role{
+	{
+		Name:             "ADMIN",
+		Description:      "Full access to all resources",
+		Permissions:      []string{PermissionAll},
+		RequireTwoFactor: true,
+	},
+	{
+		Name:        "MANAGER",
+		Description: "Runs the restaurant: catalogue, tables, orders and billing",
+		Permissions: []string{
+			"foods:create", "foods:update", "foods:delete", "foods:availability",
+			"menus:create", "menus:update", "menus:delete",
+			"tables:create", "tables:update", "tables:delete",
+			"orders:read", "orders:create", "orders:update", "orders:delete", "orders:any",
+			"orders:prepare", "orders:deliver", "orders:cancel", "orders:void",
+			"orderitems:read", "orderitems:create", "orderitems:update", "orderitems:delete", "orderitems:price",
+			"invoices:read", "invoices:create", "invoices:update", "invoices:any",
+			"promotions:manage", "servicecharges:manage", "tips:report",
+		},
+	},
+	{
+		Name:        "WAITER",
+		Description: "Opens orders and manages their items",
+		Permissions: []string{
+			"orders:read", "orders:create", "orders:update", "orders:any",
+			"orders:deliver", "orders:cancel",
+			"orderitems:read", "orderitems:create", "orderitems:update", "orderitems:delete",
+			"invoices:read", "invoices:any",
+		},
+	},
+	{
+		Name:        "CHEF",
+		Description: "Advances order status in the kitchen",
+		Permissions: []string{"orders:read", "orders:update", "orders:any", "orders:prepare", "orderitems:read", "foods:availability"},
+	},
+	{
+		Name:        "CASHIER",
+		Description: "Creates and settles invoices",
+		Permissions: []string{
+			"orders:read", "orders:any", "orderitems:read",
+			"invoices:read", "invoices:create", "invoices:update", "invoices:any",
+			"tips:report",
+		},
+	},
+	{
+		Name:        "USER",
+		Description: "Customer account",
+		Permissions: []string{
+			"orders:read", "orders:create", "orders:update", "orders:delete", "orders:cancel",
+			"orderitems:read", "orderitems:create", "orderitems:update", "orderitems:delete",
+			"invoices:read",
+		},
+	},
+}
+
+// RoleUpgrade grants permissions that were introduced after the default roles
+// may already have been seeded. Each upgrade is applied once to the existing
+// built-in roles, so permissions an admin removes afterwards stay removed.
+type RoleUpgrade struct {
+	ID     string
+	Grants map[string][]string
+}
+
+// RoleUpgrades are applied in order on startup.
+var RoleUpgrades = []RoleUpgrade{
+	{
+		ID: "order-status-transitions",
+		Grants: map[string][]string{
+			"MANAGER": {"orders:prepare", "orders:deliver", "orders:cancel", "orders:void"},
+			"WAITER":  {"orders:deliver", "orders:cancel"},
+			"CHEF":    {"orders:prepare"},
+			"USER":    {"orders:cancel"},
+		},
+	},
+	{
+		ID: "server-side-pricing",
+		Grants: map[string][]string{
+			"MANAGER": {"foods:availability", "orderitems:price"},
+			"CHEF":    {"foods:availability"},
+		},
+	},
+	{
+		ID: "promotions",
+		Grants: map[string][]string{
+			"MANAGER": {"promotions:manage"},
+		},
+	},
+	{
+		ID: "service-charges-and-tips",
+		Grants: map[string][]string{
+			"MANAGER": {"servicecharges:manage", "tips:report"},
+			"CASHIER": {"tips:report"},
+		},
+	},
+}
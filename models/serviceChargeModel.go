@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ServiceChargeRule charges Rate percent of an order's subtotal after
+// discounts as service charge on orders for at least MinGuests guests and, if
+// ServiceType is set, of that service type. When several active rules match,
+// the one with the highest MinGuests wins; when none does, the default
+// service charge rate applies.
+type ServiceChargeRule struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439041"`
+	Name        string             `bson:"name" json:"name" example:"Large parties"`
+	Rate        float64            `bson:"rate" json:"rate" example:"12.5"`
+	MinGuests   int                `bson:"min_guests" json:"min_guests" example:"8"`
+	ServiceType string             `bson:"service_type,omitempty" json:"service_type,omitempty" example:"dine_in" enums:"dine_in,takeaway"`
+	Active      bool               `bson:"active" json:"active" example:"true"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// Matches reports whether the rule applies to an order of serviceType for
+// partySize guests.
+func (r ServiceChargeRule) Matches(partySize int, serviceType string) bool {
+	if r.ServiceType != "" && r.ServiceType != serviceType {
+		return false
+	}
+	return partySize >= r.MinGuests
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session is a signed-in device. It holds the hash of the session's current
+// refresh token, which changes on every refresh, and the hashes of the tokens
+// it replaced so that replaying one of them can be detected. Access and
+// refresh tokens carry the session ID as their family ID.
+type Session struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	UserID              primitive.ObjectID `bson:"user_id" json:"user_id" example:"507f1f77bcf86cd799439011"`
+	RefreshTokenHash    string             `bson:"refresh_token_hash" json:"-"`
+	PreviousTokenHashes []string           `bson:"previous_token_hashes,omitempty" json:"-"`
+	DeviceName          string             `bson:"device_name,omitempty" json:"device_name,omitempty" example:"Front counter iPad"`
+	UserAgent           string             `bson:"user_agent" json:"user_agent" example:"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"`
+	IP                  string             `bson:"ip" json:"ip" example:"203.0.113.7"`
+	CreatedAt           time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	LastUsedAt          time.Time          `bson:"last_used_at" json:"last_used_at" example:"2024-01-02T00:00:00Z"`
+	ExpiresAt           time.Time          `bson:"expires_at" json:"expires_at" example:"2024-01-09T00:00:00Z"`
+	Revoked             bool               `bson:"revoked" json:"-"`
+	Current             bool               `bson:"-" json:"current" example:"true"`
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"basic-backend/money"
+	"time"
+)
+
 // SignupRequest represents the user signup request body
 type SignupRequest struct {
 	FirstName string `json:"first_name" validate:"required,min=2,max=100" example:"John"`
@@ -7,21 +12,145 @@ type SignupRequest struct {
 	Email     string `json:"email" validate:"email,required" example:"john.doe@example.com"`
 	Password  string `json:"password" validate:"required,min=6" example:"password123"`
 	Phone     string `json:"phone" validate:"required" example:"+1234567890"`
-	UserType  string `json:"user_type" validate:"required,eq=ADMIN|eq=USER" example:"USER" enums:"USER,ADMIN"`
 }
 
 // SignupResponse represents the successful signup response
 type SignupResponse struct {
-	Message string `json:"message" example:"User created successfully"`
-	Token   string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User    User   `json:"user"`
+	Message      string `json:"message" example:"User created successfully"`
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	User         User   `json:"user"`
 }
 
-// LoginResponse represents the successful login response
+// LoginResponse represents the successful login response. When a second
+// factor is needed only the two-factor fields are set; recovery codes are only
+// returned when two-factor enrollment completes during login.
 type LoginResponse struct {
-	Message string      `json:"message" example:"Login successful"`
-	Token   string      `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User    UserSummary `json:"user"`
+	Message            string      `json:"message" example:"Login successful"`
+	Token              string      `json:"token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken       string      `json:"refresh_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	User               UserSummary `json:"user,omitempty"`
+	TwoFactorRequired  bool        `json:"two_factor_required,omitempty" example:"false"`
+	EnrollmentRequired bool        `json:"enrollment_required,omitempty" example:"false"`
+	ChallengeToken     string      `json:"challenge_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RecoveryCodes      []string    `json:"recovery_codes,omitempty" example:"3f9a1-7c2e0"`
+}
+
+// TwoFactorLoginRequest represents the second step of a two-factor login. The
+// code is either from the authenticator app or an unused recovery code.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Code           string `json:"code" validate:"required" example:"123456"`
+}
+
+// TwoFactorEnrollRequest represents a request to provision a TOTP secret during login
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// TwoFactorSetupResponse carries a newly provisioned TOTP secret
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Restaurant%20API:john.doe@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Restaurant+API"`
+}
+
+// TwoFactorCodeRequest represents a request confirmed with a TOTP or recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+// TwoFactorEnableResponse carries the recovery codes issued when two-factor authentication is enabled
+type TwoFactorEnableResponse struct {
+	Message       string   `json:"message" example:"Two-factor authentication enabled"`
+	RecoveryCodes []string `json:"recovery_codes" example:"3f9a1-7c2e0"`
+}
+
+// RefreshRequest represents the token refresh request body
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// RefreshResponse represents the successful token refresh response
+type RefreshResponse struct {
+	Message      string `json:"message" example:"Token refreshed successfully"`
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// ForgotPasswordRequest represents the request to start a password reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"email,required" example:"john.doe@example.com"`
+}
+
+// ResetPasswordRequest represents the request to set a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Password string `json:"password" validate:"required,min=6" example:"newpassword123"`
+}
+
+// ResendVerificationRequest represents the request to resend the verification email
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"email,required" example:"john.doe@example.com"`
+}
+
+// UserCreateRequest represents an admin request to create a user with any role
+type UserCreateRequest struct {
+	FirstName string `json:"first_name" validate:"required,min=2,max=100" example:"Jane"`
+	LastName  string `json:"last_name" validate:"required,min=2,max=100" example:"Smith"`
+	Email     string `json:"email" validate:"email,required" example:"jane.smith@example.com"`
+	Password  string `json:"password" validate:"required,min=6" example:"password123"`
+	Phone     string `json:"phone" validate:"required" example:"+1234567890"`
+	UserType  string `json:"user_type" validate:"required" example:"WAITER"`
+	// EmailVerified pre-verifies the address, e.g. for internal staff accounts
+	EmailVerified bool `json:"email_verified" example:"true"`
+}
+
+// UserRoleUpdateRequest represents an admin request to change a user's role
+type UserRoleUpdateRequest struct {
+	UserType string `json:"user_type" validate:"required" example:"CHEF"`
+}
+
+// UserStatusUpdateRequest represents an admin request to disable or re-enable a user
+type UserStatusUpdateRequest struct {
+	Disabled bool `json:"disabled" example:"true"`
+}
+
+// RoleRequest represents the request to create or update a role
+type RoleRequest struct {
+	Name             string   `json:"name" validate:"required,min=2,max=50" example:"CHEF"`
+	Description      string   `json:"description" example:"Kitchen staff"`
+	Permissions      []string `json:"permissions" example:"orders:read,orders:update"`
+	RequireTwoFactor bool     `json:"require_two_factor" example:"false"`
+}
+
+// APIKeyRequest represents the request to create a device API key
+type APIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,min=2,max=100" example:"Kitchen display 1"`
+	Permissions []string   `json:"permissions" validate:"required,min=1" example:"orders:read,orders:update,orders:any"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+// APIKeyCreateResponse represents the response to creating an API key. Key
+// is only returned here.
+type APIKeyCreateResponse struct {
+	Message string `json:"message" example:"API key created successfully, store it now as it will not be shown again"`
+	Key     string `json:"key" example:"rk_3f2a9c4b..."`
+	APIKey  APIKey `json:"api_key"`
+}
+
+// ProfileUpdateRequest represents the request to update the current user's
+// profile. Omitted fields are left unchanged.
+type ProfileUpdateRequest struct {
+	FirstName *string `json:"first_name,omitempty" example:"John"`
+	LastName  *string `json:"last_name,omitempty" example:"Doe"`
+	Phone     *string `json:"phone,omitempty" example:"+1234567890"`
+}
+
+// ChangePasswordRequest represents the request to change the current user's
+// password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required" example:"password123"`
+	NewPassword     string `json:"new_password" validate:"required" example:"newpassword456"`
 }
 
 // UserSummary represents basic user info in responses
@@ -30,7 +159,25 @@ type UserSummary struct {
 	Email     string `json:"email" example:"john.doe@example.com"`
 	FirstName string `json:"first_name" example:"John"`
 	LastName  string `json:"last_name" example:"Doe"`
-	UserType  string `json:"user_type" example:"USER" enums:"USER,ADMIN"`
+	UserType  string `json:"user_type" example:"USER"`
+}
+
+// JWK represents a public signing key in a JSON Web Key Set. RSA keys set N
+// and E, Ed25519 keys set Crv and X.
+type JWK struct {
+	Kty string `json:"kty" example:"OKP"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" example:"EdDSA"`
+	Kid string `json:"kid" example:"3f2a9c4b1d7e6a05"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty" example:"AQAB"`
+	Crv string `json:"crv,omitempty" example:"Ed25519"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSResponse represents the JSON Web Key Set response
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
 }
 
 // ErrorResponse represents an error response
@@ -45,10 +192,68 @@ type SuccessResponse struct {
 
 // FoodCreateRequest represents the request to create a food item
 type FoodCreateRequest struct {
-	Name      string  `json:"name" validate:"required,min=2,max=100" example:"Grilled Chicken"`
-	Price     float64 `json:"price" validate:"required,gt=0" example:"15.99"`
-	FoodImage string  `json:"food_image" validate:"required" example:"https://example.com/images/chicken.jpg"`
-	MenuID    string  `json:"menu_id" validate:"required" example:"507f1f77bcf86cd799439011"`
+	Name       string      `json:"name" validate:"required,min=2,max=100" example:"Grilled Chicken"`
+	Price      money.Money `json:"price" validate:"required,gt=0"`
+	FoodImage  string      `json:"food_image" validate:"required" example:"https://example.com/images/chicken.jpg"`
+	MenuID     string      `json:"menu_id" validate:"required" example:"507f1f77bcf86cd799439011"`
+	TaxClassID string      `json:"tax_class_id,omitempty" example:"507f1f77bcf86cd799439021"`
+}
+
+// TaxClassRequest represents the request to create or update a tax class
+type TaxClassRequest struct {
+	Name         string  `json:"name" validate:"required,min=2,max=50" example:"Alcohol"`
+	Description  string  `json:"description" example:"Beer, wine and spirits"`
+	DineInRate   float64 `json:"dine_in_rate" validate:"min=0,max=100" example:"20"`
+	TakeawayRate float64 `json:"takeaway_rate" validate:"min=0,max=100" example:"20"`
+	Inclusive    bool    `json:"inclusive" example:"true"`
+}
+
+// ServiceChargeRuleRequest represents the request to create or update a
+// service charge rule. Without a service_type the rule applies to dine-in and
+// takeaway orders.
+type ServiceChargeRuleRequest struct {
+	Name        string  `json:"name" validate:"required,min=2,max=100" example:"Large parties"`
+	Rate        float64 `json:"rate" validate:"min=0,max=100" example:"12.5"`
+	MinGuests   int     `json:"min_guests" validate:"min=0" example:"8"`
+	ServiceType string  `json:"service_type,omitempty" validate:"omitempty,oneof=dine_in takeaway" example:"dine_in" enums:"dine_in,takeaway"`
+	Active      *bool   `json:"active" example:"true"`
+}
+
+// PromotionRequest represents the request to create or update a promotion.
+// Percent is required for percentage promotions, amount for fixed ones and
+// buy_quantity and get_quantity for buy_x_get_y ones. Days are weekdays from
+// 0 (Sunday) to 6, and start_time and end_time bound the time of day in the
+// restaurant's time zone. Without a code the promotion applies automatically.
+type PromotionRequest struct {
+	Name        string      `json:"name" validate:"required,min=2,max=100" example:"Happy hour"`
+	Description string      `json:"description" example:"Half price drinks from 17:00 to 19:00"`
+	Type        string      `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y" example:"percentage" enums:"percentage,fixed,buy_x_get_y"`
+	Percent     float64     `json:"percent" validate:"min=0,max=100" example:"50"`
+	Amount      money.Money `json:"amount" validate:"min=0"`
+	BuyQuantity int         `json:"buy_quantity" validate:"min=0" example:"2"`
+	GetQuantity int         `json:"get_quantity" validate:"min=0" example:"1"`
+	FoodIDs     []string    `json:"food_ids" example:"507f1f77bcf86cd799439013"`
+	MenuIDs     []string    `json:"menu_ids" example:"507f1f77bcf86cd799439011"`
+	Categories  []string    `json:"categories" example:"Drinks"`
+	Days        []int       `json:"days" validate:"dive,min=0,max=6" example:"1,2,3,4,5"`
+	StartTime   string      `json:"start_time" validate:"omitempty,datetime=15:04" example:"17:00"`
+	EndTime     string      `json:"end_time" validate:"omitempty,datetime=15:04" example:"19:00"`
+	StartsAt    *time.Time  `json:"starts_at" example:"2024-01-01T00:00:00Z"`
+	EndsAt      *time.Time  `json:"ends_at" example:"2024-12-31T23:59:59Z"`
+	Code        string      `json:"code" validate:"omitempty,alphanum,min=3,max=32" example:"SUMMER10"`
+	UsageLimit  int         `json:"usage_limit" validate:"min=0" example:"100"`
+	Active      *bool       `json:"active" example:"true"`
+}
+
+// CouponRequest represents the request to enter a coupon code on an order
+type CouponRequest struct {
+	Code string `json:"code" validate:"required" example:"SUMMER10"`
+}
+
+// FoodAvailabilityRequest represents the request to mark a food item as
+// available or sold out
+type FoodAvailabilityRequest struct {
+	Available *bool `json:"available" validate:"required" example:"false"`
 }
 
 // FoodResponse represents the response after creating a food item
@@ -66,10 +271,57 @@ type MenuCreateRequest struct {
 	EndDate   string `json:"end_date" example:"2024-12-31T23:59:59Z"`
 }
 
-// OrderCreateRequest represents the request to create an order
+// OrderCreateRequest represents the request to create an order. Items, if
+// given, are created together with the order in one transaction.
 type OrderCreateRequest struct {
-	TableID string `json:"table_id" validate:"required" example:"507f1f77bcf86cd799439012"`
-	Status  string `json:"status" validate:"required" example:"pending" enums:"pending,preparing,ready,delivered,cancelled"`
+	TableID     string             `json:"table_id" validate:"required_unless=ServiceType takeaway" example:"507f1f77bcf86cd799439012"`
+	ServiceType string             `json:"service_type,omitempty" validate:"omitempty,oneof=dine_in takeaway" example:"dine_in" enums:"dine_in,takeaway"`
+	GuestCount  int                `json:"guest_count,omitempty" validate:"min=0" example:"4"`
+	Items       []OrderLineRequest `json:"items,omitempty" validate:"dive"`
+}
+
+// OrderUpdateRequest represents the request to update an order. Takeaway
+// orders have no table.
+type OrderUpdateRequest struct {
+	TableID     string `json:"table_id" validate:"required_unless=ServiceType takeaway" example:"507f1f77bcf86cd799439012"`
+	ServiceType string `json:"service_type,omitempty" validate:"omitempty,oneof=dine_in takeaway" example:"dine_in" enums:"dine_in,takeaway"`
+	GuestCount  int    `json:"guest_count,omitempty" validate:"min=0" example:"4"`
+}
+
+// OrderLineRequest represents an item added to an order. The price is taken
+// from the food; unit_price is only honoured for callers with orderitems:price.
+type OrderLineRequest struct {
+	FoodID    string      `json:"food_id" validate:"required" example:"507f1f77bcf86cd799439013"`
+	Quantity  int         `json:"quantity" validate:"required,min=1,max=1000" example:"2"`
+	UnitPrice money.Money `json:"unit_price,omitempty" validate:"omitempty,gt=0"`
+}
+
+// OrderLineUpdate represents a change to an existing item of an order.
+// unit_price is only honoured for callers with orderitems:price.
+type OrderLineUpdate struct {
+	ID        string      `json:"id" validate:"required" example:"507f1f77bcf86cd799439011"`
+	Quantity  int         `json:"quantity" validate:"required,min=1,max=1000" example:"3"`
+	UnitPrice money.Money `json:"unit_price,omitempty" validate:"omitempty,gt=0"`
+}
+
+// OrderItemsBulkRequest represents a set of changes to an order's items that
+// are applied together or not at all
+type OrderItemsBulkRequest struct {
+	Add    []OrderLineRequest `json:"add,omitempty" validate:"dive"`
+	Update []OrderLineUpdate  `json:"update,omitempty" validate:"dive"`
+	Remove []string           `json:"remove,omitempty" example:"507f1f77bcf86cd799439017"`
+}
+
+// OrderDetailResponse represents an order returned together with its items
+type OrderDetailResponse struct {
+	Message string      `json:"message" example:"Order created successfully"`
+	ID      string      `json:"id" example:"507f1f77bcf86cd799439015"`
+	Order   OrderDetail `json:"order"`
+}
+
+// OrderStatusRequest represents the request to move an order to a new status
+type OrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending preparing ready delivered cancelled" example:"preparing" enums:"pending,preparing,ready,delivered,cancelled"`
 }
 
 // TableCreateRequest represents the request to create a table
@@ -78,20 +330,39 @@ type TableCreateRequest struct {
 	Capacity    int `json:"capacity" validate:"required,min=1" example:"4"`
 }
 
-// InvoiceCreateRequest represents the request to create an invoice
+// InvoiceCreateRequest represents the request to create an invoice. The total
+// is computed from the order and the payment starts as pending.
 type InvoiceCreateRequest struct {
-	OrderID       string  `json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
-	PaymentMethod string  `json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
-	TotalAmount   float64 `json:"total_amount" validate:"required,gt=0" example:"45.99"`
-	PaymentStatus string  `json:"payment_status" validate:"required" example:"paid" enums:"pending,paid,failed,refunded"`
+	OrderID       string `json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
+	PaymentMethod string `json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
+}
+
+// OrderInvoiceRequest represents the request to bill an order
+type OrderInvoiceRequest struct {
+	PaymentMethod string `json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
+}
+
+// InvoicePaymentRequest represents the request to pay an invoice. The tip
+// goes to staff_id, by default the staff member who created the invoice.
+type InvoicePaymentRequest struct {
+	PaymentMethod string      `json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
+	Tip           money.Money `json:"tip" validate:"min=0"`
+	StaffID       string      `json:"staff_id,omitempty" example:"507f1f77bcf86cd799439020"`
+}
+
+// InvoiceUpdateRequest represents the request to update an invoice's payment
+type InvoiceUpdateRequest struct {
+	PaymentMethod string `json:"payment_method" validate:"required" example:"credit_card" enums:"cash,credit_card,debit_card,mobile_payment"`
+	PaymentStatus string `json:"payment_status" validate:"required,oneof=pending paid failed refunded" example:"refunded" enums:"pending,paid,failed,refunded"`
 }
 
-// OrderItemCreateRequest represents the request to create an order item
+// OrderItemCreateRequest represents the request to create an order item.
+// unit_price is only honoured for callers with orderitems:price.
 type OrderItemCreateRequest struct {
-	OrderID   string  `json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
-	FoodID    string  `json:"food_id" validate:"required" example:"507f1f77bcf86cd799439013"`
-	Quantity  int     `json:"quantity" validate:"required,min=1" example:"2"`
-	UnitPrice float64 `json:"unit_price" validate:"required,gt=0" example:"15.99"`
+	OrderID   string      `json:"order_id" validate:"required" example:"507f1f77bcf86cd799439012"`
+	FoodID    string      `json:"food_id" validate:"required" example:"507f1f77bcf86cd799439013"`
+	Quantity  int         `json:"quantity" validate:"required,min=1,max=1000" example:"2"`
+	UnitPrice money.Money `json:"unit_price,omitempty" validate:"omitempty,gt=0"`
 }
 
 // MenuResponse represents the response after creating or fetching a menu
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"basic-backend/money"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ServiceTypeDineIn   = "dine_in"
+	ServiceTypeTakeaway = "takeaway"
+)
+
+// TaxClass is a group of foods taxed at the same rates, such as food or
+// alcohol. Rates are percentages and may differ between dine-in and takeaway
+// orders. With Inclusive set, prices of the class's foods already contain the
+// tax; otherwise the tax is added on top.
+type TaxClass struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439021"`
+	Name         string             `bson:"name" json:"name" validate:"required,min=2,max=50" example:"Alcohol"`
+	Description  string             `bson:"description" json:"description" example:"Beer, wine and spirits"`
+	DineInRate   float64            `bson:"dine_in_rate" json:"dine_in_rate" validate:"min=0,max=100" example:"20"`
+	TakeawayRate float64            `bson:"takeaway_rate" json:"takeaway_rate" validate:"min=0,max=100" example:"20"`
+	Inclusive    bool               `bson:"inclusive" json:"inclusive" example:"true"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// Rate returns the class's tax rate for orders of the given service type.
+func (t TaxClass) Rate(serviceType string) float64 {
+	if serviceType == ServiceTypeTakeaway {
+		return t.TakeawayRate
+	}
+	return t.DineInRate
+}
+
+// TaxLine is the tax charged for one tax class on an order or invoice.
+// Taxable is the amount the tax was charged on, excluding the tax itself.
+type TaxLine struct {
+	TaxClassID string      `bson:"tax_class_id" json:"tax_class_id" example:"507f1f77bcf86cd799439021"`
+	Name       string      `bson:"name" json:"name" example:"Alcohol"`
+	Rate       float64     `bson:"rate" json:"rate" example:"20"`
+	Inclusive  bool        `bson:"inclusive" json:"inclusive" example:"true"`
+	Taxable    money.Money `bson:"taxable" json:"taxable"`
+	Tax        money.Money `bson:"tax" json:"tax"`
+}
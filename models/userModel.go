@@ -7,17 +7,22 @@ import (
 )
 
 type User struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
-	FirstName    string             `json:"first_name" validate:"required,min=2,max=100" example:"John"`
-	LastName     string             `json:"last_name" validate:"required,min=2,max=100" example:"Doe"`
-	Email        string             `json:"email" validate:"email,required" example:"john.doe@example.com"`
-	Password     string             `json:"password" validate:"required,min=6" example:"password123"`
-	Phone        string             `json:"phone" validate:"required" example:"+1234567890"`
-	Token        string             `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	RefreshToken string             `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	CreatedAt    time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt    time.Time          `json:"updated_at" example:"2024-01-01T00:00:00Z"`
-	UserType     string             `json:"user_type" validate:"required,eq=ADMIN|eq=USER" example:"USER" enums:"USER,ADMIN"`
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	FirstName         string             `json:"first_name" validate:"required,min=2,max=100" example:"John"`
+	LastName          string             `json:"last_name" validate:"required,min=2,max=100" example:"Doe"`
+	Email             string             `json:"email" validate:"email,required" example:"john.doe@example.com"`
+	Password          string             `json:"password" validate:"required,min=6" example:"password123"`
+	Phone             string             `json:"phone" validate:"required" example:"+1234567890"`
+	CreatedAt         time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt         time.Time          `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	UserType          string             `json:"user_type" validate:"required" example:"USER"`
+	Disabled          bool               `bson:"disabled" json:"disabled" example:"false"`
+	EmailVerified     bool               `bson:"email_verified" json:"email_verified" example:"true"`
+	TOTPEnabled       bool               `bson:"totp_enabled" json:"totp_enabled" example:"false"`
+	TOTPSecret        string             `bson:"totp_secret,omitempty" json:"-"`
+	TOTPPendingSecret string             `bson:"totp_pending_secret,omitempty" json:"-"`
+	TOTPLastStep      int64              `bson:"totp_last_step,omitempty" json:"-"`
+	RecoveryCodes     []string           `bson:"recovery_codes,omitempty" json:"-"`
 }
 
 type LoginRequest struct {
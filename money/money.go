@@ -0,0 +1,358 @@
+// Package money represents amounts of money exactly, as an integer number of
+// the currency's minor units (cents for USD).
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rounding modes for results that fall between two minor units.
+const (
+	RoundHalfUp   = "half_up"   // halves round away from zero
+	RoundHalfEven = "half_even" // halves round to the even neighbour (banker's rounding)
+)
+
+// JSON encodings of amounts.
+const (
+	JSONString = "string" // {"amount": "15.99", "currency": "USD"}
+	JSONCents  = "cents"  // {"amount": 1599, "currency": "USD"}
+)
+
+var (
+	currency   = "USD"
+	rounding   = RoundHalfUp
+	jsonFormat = JSONString
+)
+
+// minorUnitDigits lists the currencies that do not have two decimal places.
+var minorUnitDigits = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "PYG": 0, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+}
+
+// decimalPattern matches plain decimal numbers. big.Rat also accepts
+// fractions, exponents and base prefixes, which are not amounts.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)$`)
+
+// ErrPrecision is returned when an amount has more decimal places than its
+// currency.
+var ErrPrecision = errors.New("amount has more decimal places than the currency allows")
+
+// Money is an amount in minor units of Currency. An empty Currency means the
+// restaurant's currency. In JSON the amount is a decimal string in major units,
+// or an integer number of minor units when MONEY_JSON is cents.
+type Money struct {
+	Amount   int64  `json:"amount" example:"1599"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+// Setup reads the restaurant's currency from CURRENCY (an ISO 4217 code,
+// default USD), the rounding mode from MONEY_ROUNDING (half_up or half_even,
+// default half_up) and the JSON encoding from MONEY_JSON (string or cents,
+// default string).
+func Setup() error {
+	if value := os.Getenv("CURRENCY"); value != "" {
+		value = strings.ToUpper(value)
+		if len(value) != 3 {
+			return fmt.Errorf("CURRENCY must be an ISO 4217 code, got %q", value)
+		}
+		currency = value
+	}
+
+	switch value := strings.ToLower(os.Getenv("MONEY_ROUNDING")); value {
+	case "":
+	case RoundHalfUp, RoundHalfEven:
+		rounding = value
+	default:
+		return fmt.Errorf("MONEY_ROUNDING must be half_up or half_even, got %q", value)
+	}
+
+	switch value := strings.ToLower(os.Getenv("MONEY_JSON")); value {
+	case "":
+	case JSONString, JSONCents:
+		jsonFormat = value
+	default:
+		return fmt.Errorf("MONEY_JSON must be string or cents, got %q", value)
+	}
+	return nil
+}
+
+// Currency returns the restaurant's currency.
+func Currency() string {
+	return currency
+}
+
+// Digits returns the number of decimal places of the restaurant's currency.
+func Digits() int {
+	return digitsOf(currency)
+}
+
+func digitsOf(code string) int {
+	if digits, ok := minorUnitDigits[code]; ok {
+		return digits
+	}
+	return 2
+}
+
+// New returns an amount of minor units in the restaurant's currency.
+func New(minor int64) Money {
+	return Money{Amount: minor, Currency: currency}
+}
+
+// Parse reads a decimal amount in major units, such as "15.99", in the
+// restaurant's currency.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	minor := r.Mul(r, scale(currency))
+	if !minor.IsInt() {
+		return Money{}, ErrPrecision
+	}
+	if !minor.Num().IsInt64() {
+		return Money{}, fmt.Errorf("amount %q is too large", s)
+	}
+	return New(minor.Num().Int64()), nil
+}
+
+// FromRat rounds r, an amount in major units, to minor units.
+func FromRat(r *big.Rat) Money {
+	return New(round(new(big.Rat).Mul(r, scale(currency))))
+}
+
+// Code returns m's currency.
+func (m Money) Code() string {
+	if m.Currency == "" {
+		return currency
+	}
+	return m.Currency
+}
+
+// Rat returns m in major units.
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), scale(m.Code()).Num())
+}
+
+// String formats m in major units, such as "15.99".
+func (m Money) String() string {
+	return m.Rat().FloatString(digitsOf(m.Code()))
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) Add(other Money) Money {
+	return New(m.Amount + other.Amount)
+}
+
+func (m Money) Sub(other Money) Money {
+	return New(m.Amount - other.Amount)
+}
+
+// Mul returns m times n.
+func (m Money) Mul(n int64) Money {
+	return New(m.Amount * n)
+}
+
+// Percent returns rate percent of m, rounded to minor units.
+func (m Money) Percent(rate float64) Money {
+	r := new(big.Rat).Mul(big.NewRat(m.Amount, 100), ratFromFloat(rate))
+	return New(round(r))
+}
+
+// IncludedPercent returns the part of m that is a rate percent surcharge on
+// the rest, rounded to minor units: the tax contained in a tax-inclusive
+// price.
+func (m Money) IncludedPercent(rate float64) Money {
+	r := ratFromFloat(rate)
+	r.Quo(r, new(big.Rat).Add(big.NewRat(100, 1), r))
+	return New(round(r.Mul(r, big.NewRat(m.Amount, 1))))
+}
+
+// MulRat returns m times r, rounded to minor units.
+func (m Money) MulRat(r *big.Rat) Money {
+	return New(round(new(big.Rat).Mul(big.NewRat(m.Amount, 1), r)))
+}
+
+// Sum adds up amounts.
+func Sum(amounts ...Money) Money {
+	total := New(0)
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Amount   interface{} `json:"amount"`
+		Currency string      `json:"currency"`
+	}{Amount: m.String(), Currency: m.Code()}
+	if jsonFormat == JSONCents {
+		out.Amount = m.Amount
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts an object like the one MarshalJSON writes, or the
+// amount on its own. A string amount is in major units. A number is in minor
+// units when MONEY_JSON is cents and in major units otherwise, as older
+// clients send prices like 15.99.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*m = Money{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		var in struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &in); err != nil {
+			return err
+		}
+		if in.Currency != "" && !strings.EqualFold(in.Currency, currency) {
+			return fmt.Errorf("amounts must be in %s, got %s", currency, in.Currency)
+		}
+		if len(in.Amount) == 0 {
+			return errors.New("amount is required")
+		}
+		data = in.Amount
+	}
+
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := Parse(s)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	if jsonFormat == JSONCents {
+		minor, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("amount must be a whole number of minor units, got %s", data)
+		}
+		*m = New(minor)
+		return nil
+	}
+
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalBSONValue stores m as a Decimal128 in major units. The currency is
+// not stored; every amount is in the restaurant's currency.
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	d, err := primitive.ParseDecimal128(m.String())
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(d)
+}
+
+// UnmarshalBSONValue reads a Decimal128, or a double or integer written before
+// amounts were stored as decimals, rounding it to minor units.
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*m = New(0)
+	case bsontype.Decimal128:
+		bi, exp, err := value.Decimal128().BigInt()
+		if err != nil {
+			return err
+		}
+		r := new(big.Rat).SetInt(bi)
+		pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exp))), nil))
+		if exp < 0 {
+			r.Quo(r, pow)
+		} else {
+			r.Mul(r, pow)
+		}
+		*m = FromRat(r)
+	case bsontype.Double:
+		*m = FromRat(ratFromFloat(value.Double()))
+	case bsontype.Int32:
+		*m = FromRat(big.NewRat(int64(value.Int32()), 1))
+	case bsontype.Int64:
+		*m = FromRat(big.NewRat(value.Int64(), 1))
+	default:
+		return fmt.Errorf("cannot decode %s into an amount of money", t)
+	}
+	return nil
+}
+
+// scale is the number of minor units in a major unit of code.
+func scale(code string) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digitsOf(code))), nil))
+}
+
+// ratFromFloat converts f by its shortest decimal representation, so that
+// 15.99 becomes exactly 1599/100.
+func ratFromFloat(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	return r
+}
+
+// round rounds r to an integer using the configured rounding mode.
+func round(r *big.Rat) int64 {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return q.Int64()
+	}
+
+	// Compare twice the remainder with the denominator to find halves.
+	cmp := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(r.Denom())
+	up := cmp > 0 || (cmp == 0 && (rounding == RoundHalfUp || q.Bit(0) == 1))
+	if up {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,334 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// withSettings runs f with the given rounding mode and JSON encoding and
+// restores the previous settings afterwards.
+func withSettings(t *testing.T, mode string, format string, f func()) {
+	t.Helper()
+	oldRounding, oldFormat := rounding, jsonFormat
+	rounding, jsonFormat = mode, format
+	defer func() { rounding, jsonFormat = oldRounding, oldFormat }()
+	f()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"15.99", 1599},
+		{"15.9", 1590},
+		{"15", 1500},
+		{"0", 0},
+		{" 1.50 ", 150},
+		{"+1.50", 150},
+		{".5", 50},
+		{"-1.50", -150},
+		{"-0.05", -5},
+		{"92233720368547758.07", 9223372036854775807},
+		{"-92233720368547758.08", -9223372036854775808},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error %v", tt.in, err)
+			continue
+		}
+		if got.Amount != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in        string
+		precision bool
+	}{
+		{"", false},
+		{"abc", false},
+		{"1.2.3", false},
+		{"--1", false},
+		{"1/2", false},
+		{"1e3", false},
+		{"1E3", false},
+		{"0x10", false},
+		{"0b1", false},
+		{"1_000", false},
+		{".", false},
+		{"15.999", true},
+		{"-0.001", true},
+		{"92233720368547758.08", false},
+		{"-92233720368547758.09", false},
+		{"100000000000000000000", false},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", tt.in)
+			continue
+		}
+		if got := errors.Is(err, ErrPrecision); got != tt.precision {
+			t.Errorf("Parse(%q) error %v, precision error = %v, want %v", tt.in, err, got, tt.precision)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{1599, "15.99"},
+		{1500, "15.00"},
+		{5, "0.05"},
+		{0, "0.00"},
+		{-5, "-0.05"},
+		{-1599, "-15.99"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.in).String(); got != tt.want {
+			t.Errorf("New(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		num, denom int64
+		halfUp     int64
+		halfEven   int64
+	}{
+		{5, 2, 3, 2},
+		{7, 2, 4, 4},
+		{1, 2, 1, 0},
+		{-1, 2, -1, 0},
+		{-5, 2, -3, -2},
+		{-7, 2, -4, -4},
+		{12, 5, 2, 2},
+		{13, 5, 3, 3},
+		{-13, 5, -3, -3},
+		{4, 1, 4, 4},
+		{-4, 1, -4, -4},
+	}
+	for _, tt := range tests {
+		withSettings(t, RoundHalfUp, JSONString, func() {
+			if got := round(big.NewRat(tt.num, tt.denom)); got != tt.halfUp {
+				t.Errorf("half_up round(%d/%d) = %d, want %d", tt.num, tt.denom, got, tt.halfUp)
+			}
+		})
+		withSettings(t, RoundHalfEven, JSONString, func() {
+			if got := round(big.NewRat(tt.num, tt.denom)); got != tt.halfEven {
+				t.Errorf("half_even round(%d/%d) = %d, want %d", tt.num, tt.denom, got, tt.halfEven)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		rate     float64
+		halfUp   int64
+		halfEven int64
+	}{
+		{25, 10, 3, 2},
+		{35, 10, 4, 4},
+		{-25, 10, -3, -2},
+		{1599, 10, 160, 160},
+		{1000, 12.5, 125, 125},
+		{1, 50, 1, 0},
+		{3, 50, 2, 2},
+	}
+	for _, tt := range tests {
+		withSettings(t, RoundHalfUp, JSONString, func() {
+			if got := New(tt.amount).Percent(tt.rate).Amount; got != tt.halfUp {
+				t.Errorf("half_up New(%d).Percent(%v) = %d, want %d", tt.amount, tt.rate, got, tt.halfUp)
+			}
+		})
+		withSettings(t, RoundHalfEven, JSONString, func() {
+			if got := New(tt.amount).Percent(tt.rate).Amount; got != tt.halfEven {
+				t.Errorf("half_even New(%d).Percent(%v) = %d, want %d", tt.amount, tt.rate, got, tt.halfEven)
+			}
+		})
+	}
+}
+
+func TestIncludedPercent(t *testing.T) {
+	tests := []struct {
+		amount int64
+		rate   float64
+		want   int64
+	}{
+		{1100, 10, 100},
+		{1190, 19, 190},
+		{1000, 20, 167},
+		{-1100, 10, -100},
+		{0, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := New(tt.amount).IncludedPercent(tt.rate).Amount; got != tt.want {
+			t.Errorf("New(%d).IncludedPercent(%v) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestBSONRoundTrip(t *testing.T) {
+	tests := []int64{0, 1, 1599, -5, -1599, 9223372036854775807}
+	for _, amount := range tests {
+		data, err := bson.Marshal(bson.M{"amount": New(amount)})
+		if err != nil {
+			t.Errorf("marshalling %d: %v", amount, err)
+			continue
+		}
+
+		raw := bson.Raw(data).Lookup("amount")
+		if raw.Type != bsontype.Decimal128 {
+			t.Errorf("%d was stored as %s, want decimal128", amount, raw.Type)
+			continue
+		}
+		if got, want := raw.Decimal128().String(), New(amount).String(); got != want {
+			t.Errorf("%d was stored as %s, want %s", amount, got, want)
+		}
+
+		var out struct {
+			Amount Money `bson:"amount"`
+		}
+		if err := bson.Unmarshal(data, &out); err != nil {
+			t.Errorf("unmarshalling %d: %v", amount, err)
+			continue
+		}
+		if out.Amount.Amount != amount {
+			t.Errorf("round trip of %d gave %d", amount, out.Amount.Amount)
+		}
+	}
+}
+
+func TestUnmarshalBSONLegacy(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  int64
+	}{
+		{"double", 15.99, 1599},
+		{"negative double", -0.05, -5},
+		{"double with extra digits", 15.995, 1600},
+		{"int32", int32(15), 1500},
+		{"int64", int64(-15), -1500},
+		{"null", nil, 0},
+	}
+	for _, tt := range tests {
+		data, err := bson.Marshal(bson.M{"amount": tt.value})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		var out struct {
+			Amount Money `bson:"amount"`
+		}
+		if err := bson.Unmarshal(data, &out); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if out.Amount.Amount != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, out.Amount.Amount, tt.want)
+		}
+	}
+
+	data, err := bson.Marshal(bson.M{"amount": "15.99"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Amount Money `bson:"amount"`
+	}
+	if err := bson.Unmarshal(data, &out); err == nil {
+		t.Error("decoding a string succeeded, want an error")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		format string
+		amount int64
+		want   string
+	}{
+		{JSONString, 1599, `{"amount":"15.99","currency":"USD"}`},
+		{JSONString, -5, `{"amount":"-0.05","currency":"USD"}`},
+		{JSONCents, 1599, `{"amount":1599,"currency":"USD"}`},
+		{JSONCents, -5, `{"amount":-5,"currency":"USD"}`},
+	}
+	for _, tt := range tests {
+		withSettings(t, RoundHalfUp, tt.format, func() {
+			data, err := json.Marshal(New(tt.amount))
+			if err != nil {
+				t.Errorf("%s: marshalling %d: %v", tt.format, tt.amount, err)
+				return
+			}
+			if string(data) != tt.want {
+				t.Errorf("%s: New(%d) = %s, want %s", tt.format, tt.amount, data, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		format  string
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{JSONString, `"15.99"`, 1599, false},
+		{JSONString, `"-0.05"`, -5, false},
+		{JSONString, `15.99`, 1599, false},
+		{JSONString, `15`, 1500, false},
+		{JSONString, `{"amount":"15.99","currency":"USD"}`, 1599, false},
+		{JSONString, `{"amount":"15.99","currency":"usd"}`, 1599, false},
+		{JSONString, `{"amount":15.99}`, 1599, false},
+		{JSONString, `null`, 0, false},
+		{JSONCents, `1599`, 1599, false},
+		{JSONCents, `-5`, -5, false},
+		{JSONCents, `"15.99"`, 1599, false},
+		{JSONCents, `{"amount":1599,"currency":"USD"}`, 1599, false},
+		{JSONString, `"15.999"`, 0, true},
+		{JSONString, `"abc"`, 0, true},
+		{JSONString, `"1e3"`, 0, true},
+		{JSONString, `1e3`, 0, true},
+		{JSONString, `"92233720368547758.08"`, 0, true},
+		{JSONString, `{"amount":"15.99","currency":"EUR"}`, 0, true},
+		{JSONString, `{"currency":"USD"}`, 0, true},
+		{JSONString, `{"amount":`, 0, true},
+		{JSONCents, `15.99`, 0, true},
+		{JSONCents, `9223372036854775808`, 0, true},
+		{JSONCents, `true`, 0, true},
+	}
+	for _, tt := range tests {
+		withSettings(t, RoundHalfUp, tt.format, func() {
+			var m Money
+			err := m.UnmarshalJSON([]byte(tt.in))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("%s: decoding %s gave %d, want an error", tt.format, tt.in, m.Amount)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%s: decoding %s: %v", tt.format, tt.in, err)
+				return
+			}
+			if m.Amount != tt.want {
+				t.Errorf("%s: decoding %s gave %d, want %d", tt.format, tt.in, m.Amount, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"basic-backend/controllers"
+	"basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func APIKeyRoutes(router *gin.Engine) {
+	router.GET("/apikeys", middleware.Authentication(), middleware.RequirePermission("apikeys:manage"), controllers.GetAPIKeys())
+	router.POST("/apikeys", middleware.Authentication(), middleware.RequirePermission("apikeys:manage"), controllers.CreateAPIKey())
+	router.DELETE("/apikeys/:id", middleware.Authentication(), middleware.RequirePermission("apikeys:manage"), controllers.RevokeAPIKey())
+}
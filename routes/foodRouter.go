@@ -10,7 +10,8 @@ import (
 func FoodRoutes(router *gin.Engine) {
 	router.GET("/foods", controllers.GetFoods())
 	router.GET("/foods/:id", controllers.GetFood())
-	router.POST("/foods", middleware.Authentication(), middleware.RequireAdmin(), controllers.CreateFood())
-	router.PUT("/foods/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.UpdateFood())
-	router.DELETE("/foods/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.DeleteFood())
+	router.POST("/foods", middleware.Authentication(), middleware.RequirePermission("foods:create"), controllers.CreateFood())
+	router.PUT("/foods/:id", middleware.Authentication(), middleware.RequirePermission("foods:update"), controllers.UpdateFood())
+	router.PUT("/foods/:id/availability", middleware.Authentication(), middleware.RequirePermission("foods:availability"), controllers.SetFoodAvailability())
+	router.DELETE("/foods/:id", middleware.Authentication(), middleware.RequirePermission("foods:delete"), controllers.DeleteFood())
 }
\ No newline at end of file
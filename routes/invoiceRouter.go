@@ -8,8 +8,9 @@ import (
 )
 
 func InvoiceRoutes(router *gin.Engine) {
-	router.GET("/invoices", middleware.Authentication(), controllers.GetInvoices())
-	router.GET("/invoices/:id", middleware.Authentication(), controllers.GetInvoice())
-	router.POST("/invoices", middleware.Authentication(), controllers.CreateInvoice())
-	router.PUT("/invoices/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.UpdateInvoice())
+	router.GET("/invoices", middleware.Authentication(), middleware.RequirePermission("invoices:read"), controllers.GetInvoices())
+	router.GET("/invoices/:id", middleware.Authentication(), middleware.RequirePermission("invoices:read"), controllers.GetInvoice())
+	router.POST("/invoices", middleware.Authentication(), middleware.RequirePermission("invoices:create"), controllers.CreateInvoice())
+	router.POST("/invoices/:id/pay", middleware.Authentication(), middleware.RequirePermission("invoices:update"), controllers.PayInvoice())
+	router.PUT("/invoices/:id", middleware.Authentication(), middleware.RequirePermission("invoices:update"), controllers.UpdateInvoice())
 }
\ No newline at end of file
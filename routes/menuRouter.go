@@ -10,7 +10,7 @@ import (
 func MenuRoutes(router *gin.Engine) {
 	router.GET("/menus", controllers.GetMenus())
 	router.GET("/menus/:id", controllers.GetMenu())
-	router.POST("/menus", middleware.Authentication(), middleware.RequireAdmin(), controllers.CreateMenu())
-	router.PUT("/menus/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.UpdateMenu())
-	router.DELETE("/menus/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.DeleteMenu())
+	router.POST("/menus", middleware.Authentication(), middleware.RequirePermission("menus:create"), controllers.CreateMenu())
+	router.PUT("/menus/:id", middleware.Authentication(), middleware.RequirePermission("menus:update"), controllers.UpdateMenu())
+	router.DELETE("/menus/:id", middleware.Authentication(), middleware.RequirePermission("menus:delete"), controllers.DeleteMenu())
 }
\ No newline at end of file
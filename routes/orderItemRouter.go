@@ -8,9 +8,9 @@ import (
 )
 
 func OrderItemRoutes(router *gin.Engine) {
-	router.GET("/order-items", middleware.Authentication(), controllers.GetOrderItems())
-	router.GET("/order-items/:id", middleware.Authentication(), controllers.GetOrderItem())
-	router.POST("/order-items", middleware.Authentication(), controllers.CreateOrderItem())
-	router.PUT("/order-items/:id", middleware.Authentication(), controllers.UpdateOrderItem())
-	router.DELETE("/order-items/:id", middleware.Authentication(), controllers.DeleteOrderItem())
+	router.GET("/order-items", middleware.Authentication(), middleware.RequirePermission("orderitems:read"), controllers.GetOrderItems())
+	router.GET("/order-items/:id", middleware.Authentication(), middleware.RequirePermission("orderitems:read"), controllers.GetOrderItem())
+	router.POST("/order-items", middleware.Authentication(), middleware.RequirePermission("orderitems:create"), controllers.CreateOrderItem())
+	router.PUT("/order-items/:id", middleware.Authentication(), middleware.RequirePermission("orderitems:update"), controllers.UpdateOrderItem())
+	router.DELETE("/order-items/:id", middleware.Authentication(), middleware.RequirePermission("orderitems:delete"), controllers.DeleteOrderItem())
 }
\ No newline at end of file
@@ -8,9 +8,14 @@ import (
 )
 
 func OrderRoutes(router *gin.Engine) {
-	router.GET("/orders", middleware.Authentication(), controllers.GetOrders())
-	router.GET("/orders/:id", middleware.Authentication(), controllers.GetOrder())
-	router.POST("/orders", middleware.Authentication(), controllers.CreateOrder())
-	router.PUT("/orders/:id", middleware.Authentication(), controllers.UpdateOrder())
-	router.DELETE("/orders/:id", middleware.Authentication(), controllers.DeleteOrder())
+	router.GET("/orders", middleware.Authentication(), middleware.RequirePermission("orders:read"), controllers.GetOrders())
+	router.GET("/orders/:id", middleware.Authentication(), middleware.RequirePermission("orders:read"), controllers.GetOrder())
+	router.POST("/orders", middleware.Authentication(), middleware.RequirePermission("orders:create"), controllers.CreateOrder())
+	router.PUT("/orders/:id", middleware.Authentication(), middleware.RequirePermission("orders:update"), controllers.UpdateOrder())
+	router.PATCH("/orders/:id/items", middleware.Authentication(), middleware.RequirePermission("orderitems:update"), controllers.UpdateOrderItems())
+	router.POST("/orders/:id/status", middleware.Authentication(), middleware.RequirePermission("orders:read"), controllers.UpdateOrderStatus())
+	router.POST("/orders/:id/coupons", middleware.Authentication(), middleware.RequirePermission("orders:update"), controllers.ApplyOrderCoupon())
+	router.DELETE("/orders/:id/coupons/:code", middleware.Authentication(), middleware.RequirePermission("orders:update"), controllers.RemoveOrderCoupon())
+	router.POST("/orders/:id/invoice", middleware.Authentication(), middleware.RequirePermission("invoices:create"), controllers.CreateOrderInvoice())
+	router.DELETE("/orders/:id", middleware.Authentication(), middleware.RequirePermission("orders:delete"), controllers.DeleteOrder())
 }
\ No newline at end of file
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"basic-backend/controllers"
+	"basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PromotionRoutes(router *gin.Engine) {
+	router.GET("/promotions", middleware.Authentication(), middleware.RequirePermission("promotions:manage"), controllers.GetPromotions())
+	router.GET("/promotions/:id", middleware.Authentication(), middleware.RequirePermission("promotions:manage"), controllers.GetPromotion())
+	router.POST("/promotions", middleware.Authentication(), middleware.RequirePermission("promotions:manage"), controllers.CreatePromotion())
+	router.PUT("/promotions/:id", middleware.Authentication(), middleware.RequirePermission("promotions:manage"), controllers.UpdatePromotion())
+	router.DELETE("/promotions/:id", middleware.Authentication(), middleware.RequirePermission("promotions:manage"), controllers.DeletePromotion())
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"basic-backend/controllers"
+	"basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReportRoutes(router *gin.Engine) {
+	router.GET("/reports/tips", middleware.Authentication(), middleware.RequirePermission("tips:report"), controllers.GetTipReport())
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"basic-backend/controllers"
+	"basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RoleRoutes(router *gin.Engine) {
+	router.GET("/roles", middleware.Authentication(), middleware.RequirePermission("roles:manage"), controllers.GetRoles())
+	router.GET("/roles/permissions", middleware.Authentication(), middleware.RequirePermission("roles:manage"), controllers.GetPermissions())
+	router.POST("/roles", middleware.Authentication(), middleware.RequirePermission("roles:manage"), controllers.CreateRole())
+	router.PUT("/roles/:id", middleware.Authentication(), middleware.RequirePermission("roles:manage"), controllers.UpdateRole())
+	router.DELETE("/roles/:id", middleware.Authentication(), middleware.RequirePermission("roles:manage"), controllers.DeleteRole())
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"basic-backend/controllers"
+	"basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ServiceChargeRoutes(router *gin.Engine) {
+	router.GET("/servicecharges", middleware.Authentication(), controllers.GetServiceChargeRules())
+	router.POST("/servicecharges", middleware.Authentication(), middleware.RequirePermission("servicecharges:manage"), controllers.CreateServiceChargeRule())
+	router.PUT("/servicecharges/:id", middleware.Authentication(), middleware.RequirePermission("servicecharges:manage"), controllers.UpdateServiceChargeRule())
+	router.DELETE("/servicecharges/:id", middleware.Authentication(), middleware.RequirePermission("servicecharges:manage"), controllers.DeleteServiceChargeRule())
+}
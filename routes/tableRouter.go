@@ -10,7 +10,7 @@ import (
 func TableRoutes(router *gin.Engine) {
 	router.GET("/tables", controllers.GetTables())
 	router.GET("/tables/:id", controllers.GetTable())
-	router.POST("/tables", middleware.Authentication(), middleware.RequireAdmin(), controllers.CreateTable())
-	router.PUT("/tables/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.UpdateTable())
-	router.DELETE("/tables/:id", middleware.Authentication(), middleware.RequireAdmin(), controllers.DeleteTable())
+	router.POST("/tables", middleware.Authentication(), middleware.RequirePermission("tables:create"), controllers.CreateTable())
+	router.PUT("/tables/:id", middleware.Authentication(), middleware.RequirePermission("tables:update"), controllers.UpdateTable())
+	router.DELETE("/tables/:id", middleware.Authentication(), middleware.RequirePermission("tables:delete"), controllers.DeleteTable())
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"basic-backend/controllers"
+	"basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TaxClassRoutes(router *gin.Engine) {
+	router.GET("/taxclasses", middleware.Authentication(), controllers.GetTaxClasses())
+	router.POST("/taxclasses", middleware.Authentication(), middleware.RequirePermission("taxes:manage"), controllers.CreateTaxClass())
+	router.PUT("/taxclasses/:id", middleware.Authentication(), middleware.RequirePermission("taxes:manage"), controllers.UpdateTaxClass())
+	router.DELETE("/taxclasses/:id", middleware.Authentication(), middleware.RequirePermission("taxes:manage"), controllers.DeleteTaxClass())
+}
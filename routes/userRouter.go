@@ -8,7 +8,32 @@ import (
 )
 
 func UserRoutes(router *gin.Engine) {
+	router.GET("/.well-known/jwks.json", controllers.GetJWKS())
 	router.POST("/auth/signup", controllers.Signup())
 	router.POST("/auth/login", controllers.Login())
+	router.POST("/auth/login/2fa", controllers.LoginTwoFactor())
+	router.POST("/auth/login/2fa/enroll", controllers.EnrollTwoFactorLogin())
+	router.POST("/auth/refresh", controllers.RefreshToken())
+	router.GET("/auth/verify", controllers.VerifyEmail())
+	router.POST("/auth/verify/resend", controllers.ResendVerification())
+	router.POST("/auth/password/forgot", controllers.ForgotPassword())
+	router.POST("/auth/password/reset", controllers.ResetPassword())
+	router.POST("/auth/logout", middleware.Authentication(), controllers.Logout())
 	router.GET("/auth/user", middleware.Authentication(), controllers.GetUser())
+	router.PATCH("/auth/user", middleware.Authentication(), controllers.UpdateProfile())
+	router.POST("/auth/user/password", middleware.Authentication(), controllers.ChangePassword())
+	router.GET("/auth/sessions", middleware.Authentication(), controllers.GetSessions())
+	router.DELETE("/auth/sessions/:id", middleware.Authentication(), controllers.DeleteSession())
+	router.POST("/auth/2fa/setup", middleware.Authentication(), controllers.SetupTwoFactor())
+	router.POST("/auth/2fa/enable", middleware.Authentication(), controllers.EnableTwoFactor())
+	router.POST("/auth/2fa/disable", middleware.Authentication(), controllers.DisableTwoFactor())
+
+	router.GET("/users", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.GetUsers())
+	router.POST("/users", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.CreateUser())
+	router.PUT("/users/:id/role", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.UpdateUserRole())
+	router.PUT("/users/:id/status", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.UpdateUserStatus())
+	router.POST("/users/:id/verify", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.VerifyUser())
+	router.POST("/users/:id/unlock", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.UnlockUser())
+	router.POST("/users/:id/2fa/reset", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.ResetUserTwoFactor())
+	router.POST("/users/:id/revoke-sessions", middleware.Authentication(), middleware.RequirePermission("users:manage"), controllers.RevokeUserSessions())
 }